@@ -14,37 +14,177 @@
 
 package index
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
 
 type BatchCallback func(error)
 
 type Batch struct {
-	IndexOps          map[string]Document
-	InternalOps       map[string][]byte
-	persistedCallback BatchCallback
+	IndexOps    map[string]Document
+	InternalOps map[string][]byte
+	// ExpectedVersions holds the version each conditional operation in
+	// IndexOps expects the stored document to have, see VersionedIndex.
+	ExpectedVersions map[string]uint64
+	// PatchOps holds the fields to be merged into stored documents, see
+	// PatchIndex. Patches are applied after the operation in IndexOps for
+	// the same document, if any.
+	PatchOps map[string][]Field
+	// DeleteQueries select further documents to be deleted, see
+	// DeleteByQueryIndex and ResolveDeleteQueries.
+	DeleteQueries []*DeleteQuery
+	// InternalConditions holds the value each conditional operation in
+	// InternalOps expects the internal key to have, nil meaning the key
+	// must not exist, see InternalCASIndex.
+	InternalConditions map[string][]byte
+	persistedCallback  BatchCallback
 }
 
 func NewBatch() *Batch {
 	return &Batch{
-		IndexOps:    make(map[string]Document),
-		InternalOps: make(map[string][]byte),
+		IndexOps:           make(map[string]Document),
+		InternalOps:        make(map[string][]byte),
+		ExpectedVersions:   make(map[string]uint64),
+		PatchOps:           make(map[string][]Field),
+		InternalConditions: make(map[string][]byte),
 	}
 }
 
 func (b *Batch) Update(doc Document) {
-	b.IndexOps[doc.ID()] = doc
+	id := doc.ID()
+	b.IndexOps[id] = doc
+	delete(b.ExpectedVersions, id)
+	delete(b.PatchOps, id)
 }
 
 func (b *Batch) Delete(id string) {
 	b.IndexOps[id] = nil
+	delete(b.ExpectedVersions, id)
+	delete(b.PatchOps, id)
+}
+
+// Patch adds fields to be merged into the stored fields of an existing
+// document. Every stored field with the same name as a patch field is
+// replaced. Repeated patches of the same document accumulate.
+func (b *Batch) Patch(id string, fields ...Field) {
+	if b.PatchOps == nil {
+		b.PatchOps = make(map[string][]Field)
+	}
+	b.PatchOps[id] = append(b.PatchOps[id], fields...)
+}
+
+// UpdateIfVersion adds an update that is only applied if the stored version
+// of the document equals version. Use DocumentVersionNone to require that
+// the document does not exist yet.
+func (b *Batch) UpdateIfVersion(doc Document, version uint64) {
+	b.Update(doc)
+	b.setExpectedVersion(doc.ID(), version)
+}
+
+// DeleteIfVersion adds a delete that is only applied if the stored version
+// of the document equals version.
+func (b *Batch) DeleteIfVersion(id string, version uint64) {
+	b.Delete(id)
+	b.setExpectedVersion(id, version)
+}
+
+// ExpectedVersion returns the version expected by the operation on the
+// given document, and whether the operation is conditional at all.
+func (b *Batch) ExpectedVersion(id string) (uint64, bool) {
+	v, ok := b.ExpectedVersions[id]
+	return v, ok
+}
+
+func (b *Batch) setExpectedVersion(id string, version uint64) {
+	if b.ExpectedVersions == nil {
+		b.ExpectedVersions = make(map[string]uint64)
+	}
+	b.ExpectedVersions[id] = version
+}
+
+// DeleteByTerm adds a delete of all documents containing the term in the
+// field.
+func (b *Batch) DeleteByTerm(field string, term []byte) {
+	b.DeleteQueries = append(b.DeleteQueries, &DeleteQuery{
+		Field: field,
+		Term:  term,
+	})
+}
+
+// DeleteByDocValueRange adds a delete of all documents having a doc value
+// term for the field within [start, end). A nil start or end leaves that
+// side of the range unbounded.
+func (b *Batch) DeleteByDocValueRange(field string, start, end []byte) {
+	b.DeleteQueries = append(b.DeleteQueries, &DeleteQuery{
+		Field:         field,
+		DocValueRange: true,
+		Start:         start,
+		End:           end,
+	})
+}
+
+// deleteIfUntouched deletes the document unless the batch already operates
+// upon it.
+func (b *Batch) deleteIfUntouched(id string) {
+	if _, ok := b.IndexOps[id]; ok {
+		return
+	}
+	if _, ok := b.PatchOps[id]; ok {
+		return
+	}
+	b.Delete(id)
 }
 
 func (b *Batch) SetInternal(key, val []byte) {
 	b.InternalOps[string(key)] = val
+	delete(b.InternalConditions, string(key))
 }
 
 func (b *Batch) DeleteInternal(key []byte) {
 	b.InternalOps[string(key)] = nil
+	delete(b.InternalConditions, string(key))
+}
+
+// CompareAndSetInternal sets the internal key to val, or deletes it if val
+// is nil, provided its current value equals expected. A nil expected value
+// requires that the key does not exist. If any such condition does not
+// hold, the whole batch is rejected.
+func (b *Batch) CompareAndSetInternal(key, expected, val []byte) {
+	b.InternalOps[string(key)] = val
+	if b.InternalConditions == nil {
+		b.InternalConditions = make(map[string][]byte)
+	}
+	b.InternalConditions[string(key)] = expected
+}
+
+// CheckInternalConditions verifies the InternalConditions of the batch
+// against the current internal values returned by get, and returns an
+// *InternalConflictError for the first condition which does not hold.
+// Implementations of InternalCASIndex call it while holding the lock which
+// serializes batches.
+func (b *Batch) CheckInternalConditions(get func(key []byte) ([]byte, error)) error {
+	keys := make([]string, 0, len(b.InternalConditions))
+	for k := range b.InternalConditions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		expected := b.InternalConditions[k]
+		actual, err := get([]byte(k))
+		if err != nil {
+			return err
+		}
+		if (expected == nil) != (actual == nil) || !bytes.Equal(expected, actual) {
+			return &InternalConflictError{
+				Key:      []byte(k),
+				Expected: expected,
+				Actual:   actual,
+			}
+		}
+	}
+	return nil
 }
 
 func (b *Batch) SetPersistedCallback(f BatchCallback) {
@@ -56,19 +196,33 @@ func (b *Batch) PersistedCallback() BatchCallback {
 }
 
 func (b *Batch) String() string {
-	rv := fmt.Sprintf("Batch (%d ops, %d internal ops)\n", len(b.IndexOps), len(b.InternalOps))
+	rv := fmt.Sprintf("Batch (%d ops, %d internal ops)\n", b.numDocOps(), len(b.InternalOps))
 	for k, v := range b.IndexOps {
+		var cond string
+		if ev, ok := b.ExpectedVersions[k]; ok {
+			cond = fmt.Sprintf(" IF VERSION %d", ev)
+		}
 		if v != nil {
-			rv += fmt.Sprintf("\tINDEX - '%s'\n", k)
+			rv += fmt.Sprintf("\tINDEX - '%s'%s\n", k, cond)
 		} else {
-			rv += fmt.Sprintf("\tDELETE - '%s'\n", k)
+			rv += fmt.Sprintf("\tDELETE - '%s'%s\n", k, cond)
 		}
 	}
+	for k, v := range b.PatchOps {
+		rv += fmt.Sprintf("\tPATCH - '%s' (%d fields)\n", k, len(v))
+	}
+	for _, q := range b.DeleteQueries {
+		rv += fmt.Sprintf("\tDELETE BY - %s\n", q)
+	}
 	for k, v := range b.InternalOps {
+		var cond string
+		if _, ok := b.InternalConditions[k]; ok {
+			cond = " IF UNCHANGED"
+		}
 		if v != nil {
-			rv += fmt.Sprintf("\tSET INTERNAL - '%s'\n", k)
+			rv += fmt.Sprintf("\tSET INTERNAL - '%s'%s\n", k, cond)
 		} else {
-			rv += fmt.Sprintf("\tDELETE INTERNAL - '%s'\n", k)
+			rv += fmt.Sprintf("\tDELETE INTERNAL - '%s'%s\n", k, cond)
 		}
 	}
 	return rv
@@ -77,25 +231,105 @@ func (b *Batch) String() string {
 func (b *Batch) Reset() {
 	b.IndexOps = make(map[string]Document)
 	b.InternalOps = make(map[string][]byte)
+	b.ExpectedVersions = make(map[string]uint64)
+	b.PatchOps = make(map[string][]Field)
+	b.DeleteQueries = nil
+	b.InternalConditions = make(map[string][]byte)
 	b.persistedCallback = nil
 }
 
 func (b *Batch) Merge(o *Batch) {
-	for k, v := range o.IndexOps {
-		b.IndexOps[k] = v
-	}
-	for k, v := range o.InternalOps {
-		b.InternalOps[k] = v
+	for _, k := range o.docOpIDs() {
+		o.copyDocOp(k, b)
 	}
+	b.DeleteQueries = append(b.DeleteQueries, o.DeleteQueries...)
+	o.copyInternalOps(b)
 }
 
 func (b *Batch) TotalDocSize() int {
 	var s int
-	for k, v := range b.IndexOps {
-		if v != nil {
-			s += v.Size() + sizeOfString
+	for k := range b.IndexOps {
+		s += b.docOpSize(k)
+	}
+	for k := range b.PatchOps {
+		if _, ok := b.IndexOps[k]; !ok {
+			s += b.docOpSize(k)
 		}
-		s += len(k)
 	}
 	return s
 }
+
+// docOpIDs returns the sorted ids of all the documents operated upon by
+// the batch.
+func (b *Batch) docOpIDs() []string {
+	rv := make([]string, 0, len(b.IndexOps)+len(b.PatchOps))
+	for id := range b.IndexOps {
+		rv = append(rv, id)
+	}
+	for id := range b.PatchOps {
+		if _, ok := b.IndexOps[id]; !ok {
+			rv = append(rv, id)
+		}
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+// numDocOps returns the number of documents operated upon by the batch.
+func (b *Batch) numDocOps() int {
+	rv := len(b.IndexOps)
+	for id := range b.PatchOps {
+		if _, ok := b.IndexOps[id]; !ok {
+			rv++
+		}
+	}
+	return rv
+}
+
+// docOpSize returns the contribution of the operations on a single document
+// to TotalDocSize().
+func (b *Batch) docOpSize(id string) int {
+	s := len(id)
+	if doc := b.IndexOps[id]; doc != nil {
+		s += doc.Size() + sizeOfString
+	}
+	for _, f := range b.PatchOps[id] {
+		s += len(f.Name()) + len(f.Value())
+	}
+	return s
+}
+
+// copyDocOp copies the operations on the document with the given id, along
+// with any condition attached to them, into dst.
+func (b *Batch) copyDocOp(id string, dst *Batch) {
+	if doc, ok := b.IndexOps[id]; ok {
+		if v, ok := b.ExpectedVersions[id]; ok {
+			if doc != nil {
+				dst.UpdateIfVersion(doc, v)
+			} else {
+				dst.DeleteIfVersion(id, v)
+			}
+		} else if doc != nil {
+			dst.Update(doc)
+		} else {
+			dst.Delete(id)
+		}
+	}
+	if fields, ok := b.PatchOps[id]; ok {
+		dst.Patch(id, fields...)
+	}
+}
+
+// copyInternalOps copies the internal operations, along with any condition
+// attached to them, into dst.
+func (b *Batch) copyInternalOps(dst *Batch) {
+	for k, v := range b.InternalOps {
+		if expected, ok := b.InternalConditions[k]; ok {
+			dst.CompareAndSetInternal([]byte(k), expected, v)
+		} else if v != nil {
+			dst.SetInternal([]byte(k), v)
+		} else {
+			dst.DeleteInternal([]byte(k))
+		}
+	}
+}
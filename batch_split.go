@@ -0,0 +1,150 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrBatchNotSplittable is returned when splitting a batch which exceeds the
+// limits but carries InternalConditions.
+var ErrBatchNotSplittable = errors.New("batch with internal conditions exceeds the limits and cannot be split")
+
+// BatchLimits bounds the amount of work carried by a single Batch.
+// A zero value for a limit means that dimension is unbounded.
+type BatchLimits struct {
+	// MaxOps is the maximum number of documents operated upon (updated,
+	// deleted or patched) in a batch. Internal operations are not counted.
+	MaxOps int
+
+	// MaxDocBytes is the maximum value of Batch.TotalDocSize(). A single
+	// document larger than this limit is placed in a batch of its own.
+	MaxDocBytes int
+}
+
+// Unbounded returns true if neither limit is set.
+func (l BatchLimits) Unbounded() bool {
+	return l.MaxOps <= 0 && l.MaxDocBytes <= 0
+}
+
+// Exceeded returns true if the batch violates any of the limits.
+func (l BatchLimits) Exceeded(b *Batch) bool {
+	if l.MaxOps > 0 && b.numDocOps() > l.MaxOps {
+		return true
+	}
+	if l.MaxDocBytes > 0 && b.TotalDocSize() > l.MaxDocBytes {
+		return true
+	}
+	return false
+}
+
+// BatchSplitter breaks batches that exceed its limits into a sequence of
+// smaller batches, each of which respects the limits.
+type BatchSplitter struct {
+	limits BatchLimits
+}
+
+func NewBatchSplitter(limits BatchLimits) *BatchSplitter {
+	return &BatchSplitter{
+		limits: limits,
+	}
+}
+
+func (s *BatchSplitter) Limits() BatchLimits {
+	return s.limits
+}
+
+// Split returns the batches which together carry all the operations of b.
+// A batch within the limits is returned as is. Otherwise document
+// operations are distributed over the sub-batches in id order, the
+// DeleteQueries are attached to the first sub-batch, the InternalOps are
+// attached to the final sub-batch, and the persisted callback of b (if any)
+// is invoked exactly once, after every sub-batch has reported being
+// persisted, with the first error reported by any of them.
+//
+// A batch carrying InternalConditions cannot be split, as a failed
+// condition must reject the whole batch, which sub-batches applied one after
+// the other cannot guarantee. Split returns ErrBatchNotSplittable for such a
+// batch when it exceeds the limits, leaving the caller to either execute it
+// whole or reduce it.
+func (s *BatchSplitter) Split(b *Batch) ([]*Batch, error) {
+	if s.limits.Unbounded() || !s.limits.Exceeded(b) {
+		return []*Batch{b}, nil
+	}
+	if len(b.InternalConditions) > 0 {
+		return nil, ErrBatchNotSplittable
+	}
+
+	var rv []*Batch
+	curr := NewBatch()
+	curr.DeleteQueries = b.DeleteQueries
+	var currOps, currBytes int
+	for _, id := range b.docOpIDs() {
+		opBytes := b.docOpSize(id)
+		if currOps > 0 &&
+			((s.limits.MaxOps > 0 && currOps+1 > s.limits.MaxOps) ||
+				(s.limits.MaxDocBytes > 0 && currBytes+opBytes > s.limits.MaxDocBytes)) {
+			rv = append(rv, curr)
+			curr = NewBatch()
+			currOps, currBytes = 0, 0
+		}
+		b.copyDocOp(id, curr)
+		currOps++
+		currBytes += opBytes
+	}
+	b.copyInternalOps(curr)
+	rv = append(rv, curr)
+
+	if cb := b.PersistedCallback(); cb != nil {
+		chained := newChainedBatchCallback(cb, len(rv))
+		for _, sub := range rv {
+			sub.SetPersistedCallback(chained.done)
+		}
+	}
+
+	return rv, nil
+}
+
+// chainedBatchCallback fans in the persisted callbacks of several
+// sub-batches into the single callback of the batch they were split from.
+type chainedBatchCallback struct {
+	m         sync.Mutex
+	cb        BatchCallback
+	remaining int
+	err       error
+}
+
+func newChainedBatchCallback(cb BatchCallback, parts int) *chainedBatchCallback {
+	return &chainedBatchCallback{
+		cb:        cb,
+		remaining: parts,
+	}
+}
+
+func (c *chainedBatchCallback) done(err error) {
+	c.m.Lock()
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	c.remaining--
+	fire := c.remaining == 0
+	err = c.err
+	c.m.Unlock()
+
+	if fire {
+		c.cb(err)
+	}
+}
@@ -0,0 +1,330 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testDocument struct {
+	id     string
+	size   int
+	fields []Field
+}
+
+func (d *testDocument) ID() string                                   { return d.id }
+func (d *testDocument) Size() int                                    { return d.size }
+func (d *testDocument) VisitComposite(visitor CompositeFieldVisitor) {}
+func (d *testDocument) HasComposite() bool                           { return false }
+func (d *testDocument) NumPlainTextBytes() uint64                    { return 0 }
+func (d *testDocument) AddIDField()                                  {}
+func (d *testDocument) StoredFieldsBytes() uint64                    { return 0 }
+func (d *testDocument) Indexed() bool                                { return true }
+
+func (d *testDocument) VisitFields(visitor FieldVisitor) {
+	for _, f := range d.fields {
+		visitor(f)
+	}
+}
+
+type testField struct {
+	name    string
+	value   []byte
+	options FieldIndexingOptions
+}
+
+func (f *testField) Name() string                               { return f.name }
+func (f *testField) Value() []byte                              { return f.value }
+func (f *testField) ArrayPositions() []uint64                   { return nil }
+func (f *testField) EncodedFieldType() byte                     { return 't' }
+func (f *testField) Analyze()                                   {}
+func (f *testField) Options() FieldIndexingOptions              { return f.options }
+func (f *testField) AnalyzedLength() int                        { return 0 }
+func (f *testField) AnalyzedTokenFrequencies() TokenFrequencies { return nil }
+func (f *testField) NumPlainTextBytes() uint64                  { return uint64(len(f.value)) }
+
+func TestBatchSplitterWithinLimits(t *testing.T) {
+	b := NewBatch()
+	b.Update(&testDocument{id: "a", size: 10})
+	b.Delete("b")
+
+	parts, err := NewBatchSplitter(BatchLimits{MaxOps: 2}).Split(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 1 || parts[0] != b {
+		t.Fatalf("expected the batch to be returned as is, got %d parts", len(parts))
+	}
+}
+
+func TestBatchSplitterInternalConditions(t *testing.T) {
+	b := NewBatch()
+	for i := 0; i < 5; i++ {
+		b.Update(&testDocument{id: fmt.Sprintf("doc-%d", i), size: 10})
+	}
+	b.CompareAndSetInternal([]byte("offset"), []byte("0"), []byte("5"))
+
+	splitter := NewBatchSplitter(BatchLimits{MaxOps: 2})
+	parts, err := splitter.Split(b)
+	if err != ErrBatchNotSplittable || parts != nil {
+		t.Fatalf("expected ErrBatchNotSplittable, got %d parts, %v", len(parts), err)
+	}
+
+	// within the limits, a conditional batch is returned as is
+	splitter = NewBatchSplitter(BatchLimits{MaxOps: 5})
+	parts, err = splitter.Split(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 1 || parts[0] != b {
+		t.Fatalf("expected the batch to be returned as is, got %d parts", len(parts))
+	}
+}
+
+func TestBatchSplitterMaxOps(t *testing.T) {
+	b := NewBatch()
+	for i := 0; i < 5; i++ {
+		b.Update(&testDocument{id: fmt.Sprintf("doc-%d", i), size: 10})
+	}
+	b.SetInternal([]byte("offset"), []byte("5"))
+
+	parts, err := NewBatchSplitter(BatchLimits{MaxOps: 2}).Split(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(parts))
+	}
+	seen := map[string]bool{}
+	for i, part := range parts {
+		if len(part.IndexOps) > 2 {
+			t.Errorf("part %d has %d ops", i, len(part.IndexOps))
+		}
+		for id := range part.IndexOps {
+			seen[id] = true
+		}
+		if i < len(parts)-1 && len(part.InternalOps) != 0 {
+			t.Errorf("expected no internal ops in part %d", i)
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected 5 distinct ops, got %d", len(seen))
+	}
+	if string(parts[2].InternalOps["offset"]) != "5" {
+		t.Errorf("expected internal ops on the final part")
+	}
+}
+
+func TestBatchSplitterMaxDocBytes(t *testing.T) {
+	b := NewBatch()
+	b.Update(&testDocument{id: "a", size: 100})
+	b.Update(&testDocument{id: "b", size: 1000})
+	b.Update(&testDocument{id: "c", size: 100})
+
+	limit := 2 * (len("a") + 100 + sizeOfString)
+	parts, err := NewBatchSplitter(BatchLimits{MaxDocBytes: limit}).Split(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(parts))
+	}
+	if _, ok := parts[1].IndexOps["b"]; !ok {
+		t.Errorf("expected the oversized document in a part of its own")
+	}
+}
+
+func TestBatchSplitterPersistedCallback(t *testing.T) {
+	var calls int
+	var got error
+	b := NewBatch()
+	for i := 0; i < 4; i++ {
+		b.Delete(fmt.Sprintf("doc-%d", i))
+	}
+	b.SetPersistedCallback(func(err error) {
+		calls++
+		got = err
+	})
+
+	parts, err := NewBatchSplitter(BatchLimits{MaxOps: 1}).Split(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 4 {
+		t.Fatalf("expected 4 parts, got %d", len(parts))
+	}
+	errPart := errors.New("part failed")
+	for i, part := range parts {
+		if calls != 0 {
+			t.Fatalf("callback fired before all parts were persisted")
+		}
+		var err error
+		if i == 1 {
+			err = errPart
+		}
+		part.PersistedCallback()(err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected callback to fire once, fired %d times", calls)
+	}
+	if got != errPart {
+		t.Errorf("expected %v, got %v", errPart, got)
+	}
+}
+
+func TestBatchExpectedVersions(t *testing.T) {
+	b := NewBatch()
+	b.UpdateIfVersion(&testDocument{id: "a"}, DocumentVersionNone)
+	b.DeleteIfVersion("b", 7)
+	b.UpdateIfVersion(&testDocument{id: "c"}, 3)
+	b.Update(&testDocument{id: "c"})
+
+	if v, ok := b.ExpectedVersion("a"); !ok || v != DocumentVersionNone {
+		t.Errorf("expected 'a' to require absence, got %d, %v", v, ok)
+	}
+	if v, ok := b.ExpectedVersion("b"); !ok || v != 7 {
+		t.Errorf("expected 'b' to require version 7, got %d, %v", v, ok)
+	}
+	if _, ok := b.ExpectedVersion("c"); ok {
+		t.Errorf("expected unconditional update to clear the condition on 'c'")
+	}
+
+	parts, err := NewBatchSplitter(BatchLimits{MaxOps: 1}).Split(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range parts {
+		for id := range part.IndexOps {
+			_, inPart := part.ExpectedVersion(id)
+			_, inBatch := b.ExpectedVersion(id)
+			if inPart != inBatch {
+				t.Errorf("condition on '%s' not carried into sub-batch", id)
+			}
+		}
+	}
+
+	m := NewBatch()
+	m.Merge(b)
+	if v, ok := m.ExpectedVersion("b"); !ok || v != 7 {
+		t.Errorf("expected merge to carry the condition on 'b'")
+	}
+}
+
+func TestBatchPatch(t *testing.T) {
+	name := &testField{name: "name", value: []byte("marty"), options: StoreField}
+	city := &testField{name: "city", value: []byte("hill valley"), options: StoreField}
+
+	b := NewBatch()
+	b.Update(&testDocument{id: "a"})
+	b.Patch("a", name)
+	b.Patch("a", city)
+	b.Patch("b", name)
+	b.Patch("c", name)
+	b.Delete("c")
+
+	if len(b.PatchOps["a"]) != 2 {
+		t.Errorf("expected patches to accumulate, got %d fields", len(b.PatchOps["a"]))
+	}
+	if _, ok := b.PatchOps["c"]; ok {
+		t.Errorf("expected delete to discard the patch of 'c'")
+	}
+	if b.numDocOps() != 3 {
+		t.Errorf("expected 3 documents operated upon, got %d", b.numDocOps())
+	}
+
+	parts, err := NewBatchSplitter(BatchLimits{MaxOps: 1}).Split(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(parts))
+	}
+	if _, ok := parts[0].IndexOps["a"]; !ok || len(parts[0].PatchOps["a"]) != 2 {
+		t.Errorf("expected update and patch of 'a' to stay together")
+	}
+	if len(parts[1].PatchOps["b"]) != 1 {
+		t.Errorf("expected patch of 'b' in its own part")
+	}
+}
+
+func TestVisitPatchedFields(t *testing.T) {
+	existing := &testDocument{
+		id: "a",
+		fields: []Field{
+			&testField{name: "name", value: []byte("marty"), options: StoreField},
+			&testField{name: "tags", value: []byte("a"), options: StoreField},
+			&testField{name: "tags", value: []byte("b"), options: StoreField},
+		},
+	}
+	patch := []Field{
+		&testField{name: "tags", value: []byte("c"), options: StoreField | IndexField},
+	}
+	if err := ValidatePatch(patch); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidatePatch([]Field{&testField{name: "x", options: IndexField}}); !errors.Is(err, ErrPatchFieldNotStored) {
+		t.Errorf("expected ErrPatchFieldNotStored, got %v", err)
+	}
+
+	var got []string
+	VisitPatchedFields(existing, patch, func(f Field, changed bool) {
+		got = append(got, fmt.Sprintf("%s=%s/%v", f.Name(), f.Value(), changed))
+	})
+	expected := []string{"name=marty/false", "tags=c/true"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestBatchCompareAndSetInternal(t *testing.T) {
+	stored := map[string][]byte{"offset": []byte("10")}
+	get := func(key []byte) ([]byte, error) {
+		return stored[string(key)], nil
+	}
+
+	b := NewBatch()
+	b.CompareAndSetInternal([]byte("offset"), []byte("10"), []byte("20"))
+	b.CompareAndSetInternal([]byte("owner"), nil, []byte("writer-1"))
+	if err := b.CheckInternalConditions(get); err != nil {
+		t.Fatalf("expected conditions to hold, got %v", err)
+	}
+
+	stored["offset"] = []byte("15")
+	err := b.CheckInternalConditions(get)
+	conflict, ok := err.(*InternalConflictError)
+	if !ok || string(conflict.Key) != "offset" || string(conflict.Actual) != "15" {
+		t.Fatalf("expected conflict on 'offset', got %v", err)
+	}
+
+	stored["offset"] = []byte("10")
+	stored["owner"] = []byte("writer-2")
+	if err := b.CheckInternalConditions(get); err == nil {
+		t.Fatalf("expected conflict on 'owner'")
+	}
+
+	b.SetInternal([]byte("owner"), []byte("writer-1"))
+	if err := b.CheckInternalConditions(get); err != nil {
+		t.Fatalf("expected unconditional set to clear the condition, got %v", err)
+	}
+
+	m := NewBatch()
+	m.Merge(b)
+	if expected, ok := m.InternalConditions["offset"]; !ok || string(expected) != "10" {
+		t.Errorf("expected merge to carry the condition on 'offset'")
+	}
+}
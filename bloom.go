@@ -0,0 +1,232 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"math"
+	"sync"
+)
+
+// DefaultBloomFalsePositiveRate is the false positive rate used when none
+// is specified.
+const DefaultBloomFalsePositiveRate = 0.01
+
+// BloomFilter is a probabilistic set membership structure: it never reports
+// a key which was added as missing, but may report a key which was not added
+// as possibly present.
+type BloomFilter struct {
+	bits []uint64
+	k    uint64
+}
+
+// NewBloomFilter returns an empty filter sized for n keys at the given false
+// positive rate.
+func NewBloomFilter(n int, fpRate float64) *BloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = DefaultBloomFalsePositiveRate
+	}
+	m := math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(n)*math.Ln2))
+	return &BloomFilter{
+		bits: make([]uint64, (uint64(m)+63)/64),
+		k:    uint64(k),
+	}
+}
+
+// BuildBloomFilter returns a filter holding all the terms of the FieldDict,
+// sized according to its cardinality. The caller remains responsible for
+// closing the FieldDict.
+func BuildBloomFilter(fd FieldDict, fpRate float64) (*BloomFilter, error) {
+	rv := NewBloomFilter(fd.Cardinality(), fpRate)
+	for {
+		entry, err := fd.Next()
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return rv, nil
+		}
+		rv.Add([]byte(entry.Term))
+	}
+}
+
+// Add inserts the key in the filter.
+func (f *BloomFilter) Add(key []byte) {
+	h1, h2 := bloomHashes(key)
+	m := uint64(len(f.bits)) * 64
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// MayContain returns false if the key was definitely not added to the
+// filter, and true if it possibly was.
+func (f *BloomFilter) MayContain(key []byte) bool {
+	h1, h2 := bloomHashes(key)
+	m := uint64(len(f.bits)) * 64
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// probeBytes returns the number of bytes read by a lookup which probes
+// every bit.
+func (f *BloomFilter) probeBytes() uint64 {
+	return f.k * 8
+}
+
+// Size returns the size of the filter in bytes.
+func (f *BloomFilter) Size() int {
+	return len(f.bits) * 8
+}
+
+// bloomHashes derives the two hashes used for double hashing from the
+// 64-bit FNV-1a hash of the key.
+func bloomHashes(key []byte) (uint64, uint64) {
+	const offset64, prime64 = 14695981039346656037, 1099511628211
+	h := uint64(offset64)
+	for _, c := range key {
+		h ^= uint64(c)
+		h *= prime64
+	}
+	// an odd second hash visits distinct bits for any number of probes
+	return h & 0xffffffff, (h >> 32) | 1
+}
+
+// NewBloomFieldDictContains returns a FieldDictContains answering misses
+// from the filter alone, and confirming possible hits with the definitive
+// fallback. BytesRead accounts for both.
+func NewBloomFieldDictContains(filter *BloomFilter, fallback FieldDictContains) FieldDictContains {
+	return &bloomFieldDictContains{
+		filter:   filter,
+		fallback: fallback,
+	}
+}
+
+type bloomFieldDictContains struct {
+	filter    *BloomFilter
+	fallback  FieldDictContains
+	bytesRead uint64
+}
+
+func (c *bloomFieldDictContains) Contains(key []byte) (bool, error) {
+	c.bytesRead += c.filter.probeBytes()
+	if !c.filter.MayContain(key) {
+		return false, nil
+	}
+	return c.fallback.Contains(key)
+}
+
+func (c *bloomFieldDictContains) BytesRead() uint64 {
+	return c.bytesRead + c.fallback.BytesRead()
+}
+
+// BloomContainsReader layers per-field bloom filters over the dictionary
+// lookups of an IndexReader, and implements IndexReaderContains. Filters
+// are built from the field dictionaries on first use, and remain valid for
+// as long as the reader, whose snapshot is immutable.
+type BloomContainsReader struct {
+	reader IndexReader
+	fpRate float64
+
+	m       sync.Mutex
+	filters map[string]*BloomFilter
+}
+
+// NewBloomContainsReader returns a BloomContainsReader building its filters
+// at the given false positive rate.
+func NewBloomContainsReader(reader IndexReader, fpRate float64) *BloomContainsReader {
+	return &BloomContainsReader{
+		reader:  reader,
+		fpRate:  fpRate,
+		filters: make(map[string]*BloomFilter),
+	}
+}
+
+func (r *BloomContainsReader) FieldDictContains(field string) (FieldDictContains, error) {
+	filter, err := r.filter(field)
+	if err != nil {
+		return nil, err
+	}
+	var fallback FieldDictContains
+	if cr, ok := r.reader.(IndexReaderContains); ok {
+		fallback, err = cr.FieldDictContains(field)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		fallback = &rangeFieldDictContains{reader: r.reader, field: field}
+	}
+	return NewBloomFieldDictContains(filter, fallback), nil
+}
+
+func (r *BloomContainsReader) filter(field string) (*BloomFilter, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if filter, ok := r.filters[field]; ok {
+		return filter, nil
+	}
+	fd, err := r.reader.FieldDict(field)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := BuildBloomFilter(fd, r.fpRate)
+	if cerr := fd.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.filters[field] = filter
+	return filter, nil
+}
+
+// rangeFieldDictContains answers membership through single-term
+// FieldDictRange lookups, for readers without IndexReaderContains.
+type rangeFieldDictContains struct {
+	reader    IndexReader
+	field     string
+	bytesRead uint64
+}
+
+func (c *rangeFieldDictContains) Contains(key []byte) (found bool, err error) {
+	fd, err := c.reader.FieldDictRange(c.field, key, key)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		c.bytesRead += fd.BytesRead()
+		if cerr := fd.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	entry, err := fd.Next()
+	if err != nil {
+		return false, err
+	}
+	return entry != nil, nil
+}
+
+func (c *rangeFieldDictContains) BytesRead() uint64 {
+	return c.bytesRead
+}
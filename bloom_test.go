@@ -0,0 +1,117 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"fmt"
+	"testing"
+)
+
+type testCountingContains struct {
+	terms map[string]bool
+	calls int
+}
+
+func (c *testCountingContains) Contains(key []byte) (bool, error) {
+	c.calls++
+	return c.terms[string(key)], nil
+}
+
+func (c *testCountingContains) BytesRead() uint64 {
+	return uint64(c.calls)
+}
+
+func TestBloomFilter(t *testing.T) {
+	f := NewBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		f.Add([]byte(fmt.Sprintf("term-%d", i)))
+	}
+	for i := 0; i < 1000; i++ {
+		if !f.MayContain([]byte(fmt.Sprintf("term-%d", i))) {
+			t.Fatalf("false negative for term-%d", i)
+		}
+	}
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if f.MayContain([]byte(fmt.Sprintf("other-%d", i))) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 300 {
+		t.Errorf("expected about 1%% false positives, got %d in 10000", falsePositives)
+	}
+}
+
+func TestBloomFieldDictContains(t *testing.T) {
+	f := NewBloomFilter(2, 0.01)
+	f.Add([]byte("alice"))
+	f.Add([]byte("bob"))
+	fallback := &testCountingContains{terms: map[string]bool{"alice": true, "bob": true}}
+	c := NewBloomFieldDictContains(f, fallback)
+
+	found, err := c.Contains([]byte("alice"))
+	if err != nil || !found {
+		t.Errorf("expected alice to be found, got %t, %v", found, err)
+	}
+	if fallback.calls != 1 {
+		t.Errorf("expected possible hit to be confirmed, got %d calls", fallback.calls)
+	}
+	for _, term := range []string{"carol", "dave", "erin"} {
+		found, err := c.Contains([]byte(term))
+		if err != nil || found {
+			t.Errorf("expected %s not to be found, got %t, %v", term, found, err)
+		}
+	}
+	if fallback.calls > 2 {
+		t.Errorf("expected misses to be answered by the filter, got %d calls", fallback.calls)
+	}
+	if c.BytesRead() <= fallback.BytesRead() {
+		t.Errorf("expected filter probes to be counted in bytes read")
+	}
+}
+
+func TestBloomContainsReader(t *testing.T) {
+	reader := NewBloomContainsReader(newTestDictReader("name", "alice", "bob", "carol"), 0)
+	c, err := reader.FieldDictContains("name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for term, expected := range map[string]bool{
+		"alice": true,
+		"bob":   true,
+		"carol": true,
+		"al":    false,
+		"dave":  false,
+	} {
+		found, err := c.Contains([]byte(term))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found != expected {
+			t.Errorf("expected %s found %t, got %t", term, expected, found)
+		}
+	}
+
+	c, err = reader.FieldDictContains("missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found, err := c.Contains([]byte("alice")); err != nil || found {
+		t.Errorf("expected no term in missing field, got %t, %v", found, err)
+	}
+	if len(reader.filters) != 2 {
+		t.Errorf("expected a filter per field, got %d", len(reader.filters))
+	}
+}
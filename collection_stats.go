@@ -0,0 +1,191 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import "context"
+
+// ContextKey is the type of the keys under which values are stored in a
+// context by this package.
+type ContextKey string
+
+// CollectionStatsKey is the context key holding the *CollectionStats to
+// score with, see WithCollectionStats.
+const CollectionStatsKey = ContextKey("_collection_stats_key")
+
+// CollectionStats holds the statistics scoring depends on, for a set of
+// fields and terms. Gathered from each of the indexes a query spans and
+// merged, they let every index score with the same global statistics, as in
+// the DFS-query-then-fetch model. They are handed to scoring through the
+// context rather than by wrapping the readers, which keeps the optional
+// interfaces of the readers available.
+type CollectionStats struct {
+	DocCount uint64
+	Fields   map[string]FieldStats
+	// FieldCardinality holds the values reported by BM25Reader, for the
+	// fields of readers supporting it.
+	FieldCardinality map[string]int
+	// Terms maps field names to the statistics of their terms.
+	Terms map[string]map[string]TermStats
+}
+
+// NewCollectionStats returns empty statistics.
+func NewCollectionStats() *CollectionStats {
+	return &CollectionStats{
+		Fields:           make(map[string]FieldStats),
+		FieldCardinality: make(map[string]int),
+		Terms:            make(map[string]map[string]TermStats),
+	}
+}
+
+// GatherCollectionStats returns the statistics of the reader for the given
+// terms, keyed by field name, and for their fields. The statistics of the
+// fields are only gathered from readers implementing TermStatsReader, as
+// the fallback of ReadFieldStats walks every posting of the field; scoring
+// then reads them from its own reader.
+func GatherCollectionStats(ctx context.Context, reader IndexReader,
+	terms map[string][]string) (*CollectionStats, error) {
+	rv := NewCollectionStats()
+	var err error
+	rv.DocCount, err = reader.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	for field, fieldTerms := range terms {
+		if sr, ok := reader.(TermStatsReader); ok {
+			rv.Fields[field], err = sr.FieldStats(field)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if br, ok := reader.(BM25Reader); ok {
+			rv.FieldCardinality[field], err = br.FieldCardinality(field)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rv.Terms[field] = make(map[string]TermStats, len(fieldTerms))
+		for _, term := range fieldTerms {
+			rv.Terms[field][term], err = ReadTermStats(ctx, reader, field, []byte(term))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rv, nil
+}
+
+// Merge adds the statistics of another, disjoint, collection to these ones.
+func (s *CollectionStats) Merge(o *CollectionStats) {
+	s.DocCount += o.DocCount
+	for field, fs := range o.Fields {
+		cur := s.Fields[field]
+		cur.DocCount += fs.DocCount
+		cur.SumDocFreq += fs.SumDocFreq
+		cur.SumTotalTermFreq += fs.SumTotalTermFreq
+		s.Fields[field] = cur
+	}
+	for field, c := range o.FieldCardinality {
+		s.FieldCardinality[field] += c
+	}
+	for field, terms := range o.Terms {
+		if s.Terms[field] == nil {
+			s.Terms[field] = make(map[string]TermStats, len(terms))
+		}
+		for term, ts := range terms {
+			cur := s.Terms[field][term]
+			cur.DocFreq += ts.DocFreq
+			cur.TotalTermFreq += ts.TotalTermFreq
+			s.Terms[field][term] = cur
+		}
+	}
+}
+
+// termStats returns the statistics of the term, if they were gathered.
+func (s *CollectionStats) termStats(field string, term []byte) (TermStats, bool) {
+	ts, ok := s.Terms[field][string(term)]
+	return ts, ok
+}
+
+// WithCollectionStats returns a copy of the context carrying the statistics,
+// for scoring code to read through the Scoring* functions in place of the
+// statistics of the reader it scores with.
+func WithCollectionStats(ctx context.Context, stats *CollectionStats) context.Context {
+	return context.WithValue(ctx, CollectionStatsKey, stats)
+}
+
+// CollectionStatsFromContext returns the statistics carried by the context,
+// if any.
+func CollectionStatsFromContext(ctx context.Context) *CollectionStats {
+	stats, _ := ctx.Value(CollectionStatsKey).(*CollectionStats)
+	return stats
+}
+
+// ScoringDocCount returns the number of documents to score with: that of the
+// collection statistics carried by the context, or that of the reader.
+func ScoringDocCount(ctx context.Context, reader IndexReader) (uint64, error) {
+	if stats := CollectionStatsFromContext(ctx); stats != nil {
+		return stats.DocCount, nil
+	}
+	return reader.DocCount()
+}
+
+// ScoringDocFreq returns the document frequency of the term to score with:
+// that of the collection statistics carried by the context if they hold the
+// term, or the Count of its TermFieldReader.
+func ScoringDocFreq(ctx context.Context, field string, term []byte, tfr TermFieldReader) uint64 {
+	if stats := CollectionStatsFromContext(ctx); stats != nil {
+		if ts, ok := stats.termStats(field, term); ok {
+			return ts.DocFreq
+		}
+	}
+	return tfr.Count()
+}
+
+// ScoringFieldCardinality returns the cardinality of the field to score
+// with: that of the collection statistics carried by the context if they
+// hold the field, or that of the reader.
+func ScoringFieldCardinality(ctx context.Context, reader BM25Reader, field string) (int, error) {
+	if stats := CollectionStatsFromContext(ctx); stats != nil {
+		if c, ok := stats.FieldCardinality[field]; ok {
+			return c, nil
+		}
+	}
+	return reader.FieldCardinality(field)
+}
+
+// ScoringTermStats returns the statistics of the term to score with: those
+// of the collection statistics carried by the context if they hold the
+// term, or those of the reader, see ReadTermStats.
+func ScoringTermStats(ctx context.Context, reader IndexReader, field string,
+	term []byte) (TermStats, error) {
+	if stats := CollectionStatsFromContext(ctx); stats != nil {
+		if ts, ok := stats.termStats(field, term); ok {
+			return ts, nil
+		}
+	}
+	return ReadTermStats(ctx, reader, field, term)
+}
+
+// ScoringFieldStats returns the statistics of the field to score with: those
+// of the collection statistics carried by the context if they hold the
+// field, or those of the reader, see ReadFieldStats.
+func ScoringFieldStats(ctx context.Context, reader IndexReader, field string) (FieldStats, error) {
+	if stats := CollectionStatsFromContext(ctx); stats != nil {
+		if fs, ok := stats.Fields[field]; ok {
+			return fs, nil
+		}
+	}
+	return ReadFieldStats(ctx, reader, field)
+}
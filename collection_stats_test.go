@@ -0,0 +1,145 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"context"
+	"testing"
+)
+
+// testTermStatsReader implements TermStatsReader through the fallbacks.
+type testTermStatsReader struct {
+	*testIndexReader
+}
+
+func (r *testTermStatsReader) TermStats(field string, term []byte) (TermStats, error) {
+	return ReadTermStats(context.Background(), r.testIndexReader, field, term)
+}
+
+func (r *testTermStatsReader) FieldStats(field string) (FieldStats, error) {
+	return ReadFieldStats(context.Background(), r.testIndexReader, field)
+}
+
+func TestCollectionStats(t *testing.T) {
+	ctx := context.Background()
+	shard1 := newTestStatsReader()
+	shard2 := &testIndexReader{
+		docs: []*testReaderDoc{
+			{id: "d", terms: map[string][]string{"body": {"the", "end"}}},
+		},
+	}
+	terms := map[string][]string{"body": {"the", "cat"}}
+
+	stats := NewCollectionStats()
+	for _, shard := range []*testIndexReader{shard1, shard2} {
+		s, err := GatherCollectionStats(ctx, &testTermStatsReader{shard}, terms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats.Merge(s)
+	}
+	if stats.DocCount != 4 {
+		t.Errorf("expected 4 docs, got %d", stats.DocCount)
+	}
+	expectedField := FieldStats{DocCount: 3, SumDocFreq: 7, SumTotalTermFreq: 8}
+	if stats.Fields["body"] != expectedField {
+		t.Errorf("expected %v, got %v", expectedField, stats.Fields["body"])
+	}
+	expectedTerm := TermStats{DocFreq: 3, TotalTermFreq: 4}
+	if stats.Terms["body"]["the"] != expectedTerm {
+		t.Errorf("expected %v, got %v", expectedTerm, stats.Terms["body"]["the"])
+	}
+
+	// scoring reads the global statistics from the context, and the reader
+	// it scores with keeps its optional interfaces
+	sctx := WithCollectionStats(ctx, stats)
+	var reader IndexReader = shard2
+	if _, ok := reader.(IndexReaderSeekableFieldDict); !ok {
+		t.Fatalf("expected the reader to keep its optional interfaces")
+	}
+	count, err := ScoringDocCount(sctx, reader)
+	if err != nil || count != 4 {
+		t.Errorf("expected global doc count 4, got %d, %v", count, err)
+	}
+	for term, expected := range map[string]uint64{"the": 3, "cat": 1, "end": 1} {
+		tfr, err := reader.TermFieldReader(sctx, []byte(term), "body", true, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docFreq := ScoringDocFreq(sctx, "body", []byte(term), tfr); docFreq != expected {
+			t.Errorf("expected doc freq %d for %s, got %d", expected, term, docFreq)
+		}
+	}
+	ts, err := ScoringTermStats(sctx, reader, "body", []byte("cat"))
+	if err != nil || ts.DocFreq != 1 {
+		t.Errorf("expected global stats for cat, got %v, %v", ts, err)
+	}
+	fs, err := ScoringFieldStats(sctx, reader, "body")
+	if err != nil || fs != expectedField {
+		t.Errorf("expected global stats for body, got %v, %v", fs, err)
+	}
+
+	count, err = ScoringDocCount(ctx, reader)
+	if err != nil || count != 1 {
+		t.Errorf("expected local doc count 1 without statistics, got %d, %v", count, err)
+	}
+
+	// the statistics of the fields are not gathered by scanning postings
+	local, err := GatherCollectionStats(ctx, shard2, terms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := local.Fields["body"]; ok {
+		t.Errorf("expected no field statistics, got %v", local.Fields)
+	}
+	if local.Terms["body"]["the"] != (TermStats{DocFreq: 1, TotalTermFreq: 1}) {
+		t.Errorf("unexpected term statistics %v", local.Terms["body"])
+	}
+	fs, err = ScoringFieldStats(WithCollectionStats(ctx, local), shard2, "body")
+	if err != nil || fs != (FieldStats{DocCount: 1, SumDocFreq: 2, SumTotalTermFreq: 2}) {
+		t.Errorf("expected local stats for body, got %v, %v", fs, err)
+	}
+}
+
+type testBM25Reader struct {
+	*testIndexReader
+}
+
+func (r *testBM25Reader) FieldCardinality(field string) (int, error) {
+	return len(r.dictTerms(field)), nil
+}
+
+func TestCollectionStatsFieldCardinality(t *testing.T) {
+	ctx := context.Background()
+	terms := map[string][]string{"body": {"the"}}
+	stats := NewCollectionStats()
+	for _, shard := range []*testBM25Reader{{newTestStatsReader()}, {newTestStatsReader()}} {
+		s, err := GatherCollectionStats(ctx, shard, terms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats.Merge(s)
+	}
+
+	reader := &testBM25Reader{newTestStatsReader()}
+	c, err := ScoringFieldCardinality(WithCollectionStats(ctx, stats), reader, "body")
+	if err != nil || c != 8 {
+		t.Errorf("expected global cardinality 8, got %d, %v", c, err)
+	}
+	c, err = ScoringFieldCardinality(ctx, reader, "body")
+	if err != nil || c != 4 {
+		t.Errorf("expected local cardinality 4, got %d, %v", c, err)
+	}
+}
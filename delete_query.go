@@ -0,0 +1,169 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// DeleteQuery selects documents to be deleted without knowing their ids.
+// It matches either the documents containing Term in Field, or, when
+// DocValueRange is set, the documents having at least one doc value term
+// for Field within [Start, End). A nil Start or End leaves that side of the
+// range unbounded.
+type DeleteQuery struct {
+	Field string
+	Term  []byte
+
+	DocValueRange bool
+	Start         []byte
+	End           []byte
+}
+
+func (q *DeleteQuery) String() string {
+	if q.DocValueRange {
+		return fmt.Sprintf("field '%s' doc values in ['%s', '%s')", q.Field, q.Start, q.End)
+	}
+	return fmt.Sprintf("field '%s' term '%s'", q.Field, q.Term)
+}
+
+func (q *DeleteQuery) inRange(term []byte) bool {
+	if q.Start != nil && bytes.Compare(term, q.Start) < 0 {
+		return false
+	}
+	if q.End != nil && bytes.Compare(term, q.End) >= 0 {
+		return false
+	}
+	return true
+}
+
+// DeleteByQueryIndex is an optional interface for indexes that can delete
+// the documents matched by a DeleteQuery atomically.
+//
+// The Batch method of a DeleteByQueryIndex evaluates Batch.DeleteQueries
+// against the index contents as of before the batch, and skips the
+// documents that the batch otherwise updates, deletes or patches: those are
+// only affected by their own operations, so a document patched in the same
+// batch is retained and patched, just as with ResolveDeleteQueries.
+type DeleteByQueryIndex interface {
+	Index
+
+	// DeleteByTerm deletes all documents containing the term in the field.
+	DeleteByTerm(field string, term []byte) error
+
+	// DeleteByDocValueRange deletes all documents having a doc value term
+	// for the field within [start, end).
+	DeleteByDocValueRange(field string, start, end []byte) error
+}
+
+// ResolveDeleteQueries is the fallback for indexes which do not implement
+// DeleteByQueryIndex. It replaces the DeleteQueries of the batch with plain
+// deletes of the matching documents found through the reader, leaving alone
+// the documents that the batch already operates upon. The resulting batch
+// can be executed by any Index, although the deletes are only atomic with
+// respect to the reader's snapshot.
+func ResolveDeleteQueries(ctx context.Context, reader IndexReader, b *Batch) error {
+	for _, q := range b.DeleteQueries {
+		var err error
+		if q.DocValueRange {
+			err = visitDocValueRangeMatches(reader, q, func(id string) {
+				b.deleteIfUntouched(id)
+			})
+		} else {
+			err = visitTermMatches(ctx, reader, q, func(id string) {
+				b.deleteIfUntouched(id)
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	b.DeleteQueries = nil
+	return nil
+}
+
+func visitTermMatches(ctx context.Context, reader IndexReader, q *DeleteQuery,
+	visitor func(id string)) (err error) {
+	tfr, err := reader.TermFieldReader(ctx, q.Term, q.Field, false, false, false)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := tfr.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	var tfd TermFieldDoc
+	for {
+		next, err := tfr.Next(tfd.Reset())
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			return nil
+		}
+		id, err := reader.ExternalID(next.ID)
+		if err != nil {
+			return err
+		}
+		visitor(id)
+	}
+}
+
+func visitDocValueRangeMatches(reader IndexReader, q *DeleteQuery,
+	visitor func(id string)) (err error) {
+	dvr, err := reader.DocValueReader([]string{q.Field})
+	if err != nil {
+		return err
+	}
+	dr, err := reader.DocIDReaderAll()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := dr.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	for {
+		internalID, err := dr.Next()
+		if err != nil {
+			return err
+		}
+		if internalID == nil {
+			return nil
+		}
+		var matched bool
+		err = dvr.VisitDocValues(internalID, func(field string, term []byte) {
+			if !matched && field == q.Field && q.inRange(term) {
+				matched = true
+			}
+		})
+		if err != nil {
+			return err
+		}
+		if matched {
+			id, err := reader.ExternalID(internalID)
+			if err != nil {
+				return err
+			}
+			visitor(id)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestResolveDeleteQueries(t *testing.T) {
+	reader := &testIndexReader{
+		docs: []*testReaderDoc{
+			{id: "a", terms: map[string][]string{"tenant": {"acme"}}, docValues: map[string][]string{"ts": {"10"}}},
+			{id: "b", terms: map[string][]string{"tenant": {"acme"}}, docValues: map[string][]string{"ts": {"20"}}},
+			{id: "c", terms: map[string][]string{"tenant": {"other"}}, docValues: map[string][]string{"ts": {"30"}}},
+			{id: "d", terms: map[string][]string{"tenant": {"other"}}, docValues: map[string][]string{"ts": {"40"}}},
+			{id: "e", terms: map[string][]string{"tenant": {"acme"}}},
+		},
+	}
+
+	b := NewBatch()
+	b.DeleteByTerm("tenant", []byte("acme"))
+	b.DeleteByDocValueRange("ts", []byte("30"), []byte("40"))
+	b.Update(&testDocument{id: "b"})
+
+	err := ResolveDeleteQueries(context.Background(), reader, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.DeleteQueries) != 0 {
+		t.Errorf("expected delete queries to be resolved")
+	}
+
+	var deleted []string
+	for id, doc := range b.IndexOps {
+		if doc == nil {
+			deleted = append(deleted, id)
+		}
+	}
+	sort.Strings(deleted)
+	expected := []string{"a", "c", "e"}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("expected deletes %v, got %v", expected, deleted)
+	}
+	if b.IndexOps["b"] == nil {
+		t.Errorf("expected update of 'b' to be retained")
+	}
+}
+
+func TestResolveDeleteQueriesPatched(t *testing.T) {
+	reader := &testIndexReader{
+		docs: []*testReaderDoc{
+			{id: "a", terms: map[string][]string{"tenant": {"acme"}}},
+			{id: "b", terms: map[string][]string{"tenant": {"acme"}}},
+		},
+	}
+
+	b := NewBatch()
+	b.DeleteByTerm("tenant", []byte("acme"))
+	b.Patch("b", &testField{name: "name", value: []byte("marty"), options: StoreField})
+
+	err := ResolveDeleteQueries(context.Background(), reader, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc, ok := b.IndexOps["a"]; !ok || doc != nil {
+		t.Errorf("expected delete of 'a'")
+	}
+	if _, ok := b.IndexOps["b"]; ok {
+		t.Errorf("expected patched 'b' not to be deleted")
+	}
+	if len(b.PatchOps["b"]) != 1 {
+		t.Errorf("expected patch of 'b' to be retained")
+	}
+}
@@ -0,0 +1,216 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StatsPendingExpirations is the StatsMap key under which an ExpiryIndex
+// reports the number of expired documents not yet swept from the index.
+const StatsPendingExpirations = "pending_expirations"
+
+// ExpirableDocument is an optional interface for documents that must be
+// removed from the index once a deadline has passed.
+type ExpirableDocument interface {
+	Document
+
+	// ExpiresAt returns the time after which the document is expired. The
+	// zero time means the document never expires.
+	ExpiresAt() time.Time
+}
+
+// ExpiryIndex is an optional interface for indexes that track the expiry
+// of ExpirableDocuments.
+//
+// Readers obtained from an ExpiryIndex implement ExpiryIndexReader, and
+// hide expired documents which have not been swept yet from DocIDReaderAll,
+// DocIDReaderOnly and TermFieldReader enumerations.
+type ExpiryIndex interface {
+	Index
+
+	// PendingExpirations returns the number of documents which are expired
+	// as of now but have not been deleted yet.
+	PendingExpirations() (uint64, error)
+}
+
+// ExpiryIndexReader is an extended index reader that exposes the expiry
+// bookkeeping of an ExpiryIndex.
+type ExpiryIndexReader interface {
+	IndexReader
+
+	// ExpiredIDs returns the external ids of at most limit documents which
+	// are expired as of now. A limit <= 0 means no limit.
+	ExpiredIDs(now time.Time, limit int) ([]string, error)
+}
+
+// ExpirySweeper periodically deletes the expired documents of an
+// ExpiryIndex.
+type ExpirySweeper struct {
+	index    ExpiryIndex
+	interval time.Duration
+	limit    int
+	now      func() time.Time
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+
+	m       sync.Mutex
+	started bool
+	closed  bool
+	lastErr error
+}
+
+// NewExpirySweeper returns a sweeper which, once started, deletes at most
+// limit expired documents per batch every interval, which must be positive.
+// A limit <= 0 deletes all expired documents in one batch.
+func NewExpirySweeper(index ExpiryIndex, interval time.Duration, limit int) (*ExpirySweeper, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("expiry sweep interval must be positive, got %v", interval)
+	}
+	return &ExpirySweeper{
+		index:    index,
+		interval: interval,
+		limit:    limit,
+		now:      time.Now,
+		closeCh:  make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}, nil
+}
+
+// Start launches the background sweeping loop. It has no effect if the loop
+// was already started, or if the sweeper is closed.
+func (s *ExpirySweeper) Start() {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.started || s.closed {
+		return
+	}
+	s.started = true
+	go s.loop()
+}
+
+// Close stops the background sweeping loop, if it was started, and waits
+// for it to exit.
+func (s *ExpirySweeper) Close() {
+	s.m.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.closeCh)
+	}
+	started := s.started
+	s.m.Unlock()
+	if started {
+		<-s.doneCh
+	}
+}
+
+// LastError returns the error of the most recent sweep, if any.
+func (s *ExpirySweeper) LastError() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.lastErr
+}
+
+func (s *ExpirySweeper) loop() {
+	defer close(s.doneCh)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case <-ticker.C:
+			_, err := s.Sweep()
+			s.m.Lock()
+			s.lastErr = err
+			s.m.Unlock()
+		}
+	}
+}
+
+// Sweep deletes one batch of expired documents and returns the number of
+// documents deleted. If the index is also a VersionedIndex, the deletes are
+// conditional on the versions observed while collecting the expired ids, so
+// documents re-indexed concurrently with a new deadline are retained.
+func (s *ExpirySweeper) Sweep() (int, error) {
+	reader, err := s.index.Reader()
+	if err != nil {
+		return 0, err
+	}
+	b, err := s.expiredBatch(reader)
+	if cerr := reader.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil || b == nil {
+		return 0, err
+	}
+
+	n := len(b.IndexOps)
+	err = s.index.Batch(b)
+	if conflicts, ok := err.(*VersionConflictsError); ok {
+		return n - len(conflicts.Conflicts), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *ExpirySweeper) expiredBatch(reader IndexReader) (*Batch, error) {
+	er, ok := reader.(ExpiryIndexReader)
+	if !ok {
+		return nil, nil
+	}
+	ids, err := er.ExpiredIDs(s.now(), s.limit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	_, versioned := s.index.(VersionedIndex)
+	vr, _ := reader.(VersionedIndexReader)
+	b := NewBatch()
+	for _, id := range ids {
+		if versioned && vr != nil {
+			version, err := vr.DocumentVersion(id)
+			if err != nil {
+				return nil, err
+			}
+			if version == DocumentVersionNone {
+				continue
+			}
+			b.DeleteIfVersion(id, version)
+		} else {
+			b.Delete(id)
+		}
+	}
+	if len(b.IndexOps) == 0 {
+		return nil, nil
+	}
+	return b, nil
+}
+
+// IsExpired returns true if the document is an ExpirableDocument whose
+// deadline has passed as of now.
+func IsExpired(doc Document, now time.Time) bool {
+	ed, ok := doc.(ExpirableDocument)
+	if !ok {
+		return false
+	}
+	expiresAt := ed.ExpiresAt()
+	return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
@@ -0,0 +1,116 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"testing"
+	"time"
+)
+
+type testExpirableDocument struct {
+	testDocument
+	expiresAt time.Time
+}
+
+func (d *testExpirableDocument) ExpiresAt() time.Time {
+	return d.expiresAt
+}
+
+type testExpiryIndex struct {
+	testIndex
+}
+
+func (i *testExpiryIndex) PendingExpirations() (uint64, error) {
+	return 0, nil
+}
+
+type testExpiryReader struct {
+	testIndexReader
+	expired []string
+}
+
+func (r *testExpiryReader) ExpiredIDs(now time.Time, limit int) ([]string, error) {
+	if limit > 0 && len(r.expired) > limit {
+		return r.expired[:limit], nil
+	}
+	return r.expired, nil
+}
+
+func TestIsExpired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		doc     Document
+		expired bool
+	}{
+		{doc: &testDocument{id: "a"}},
+		{doc: &testExpirableDocument{}},
+		{doc: &testExpirableDocument{expiresAt: now.Add(time.Minute)}},
+		{doc: &testExpirableDocument{expiresAt: now}, expired: true},
+		{doc: &testExpirableDocument{expiresAt: now.Add(-time.Minute)}, expired: true},
+	}
+	for i, test := range tests {
+		if got := IsExpired(test.doc, now); got != test.expired {
+			t.Errorf("test %d: expected %v, got %v", i, test.expired, got)
+		}
+	}
+}
+
+func TestExpirySweeperSweep(t *testing.T) {
+	idx := &testExpiryIndex{}
+	idx.newReader = func() (IndexReader, error) {
+		return &testExpiryReader{expired: []string{"a", "b", "c"}}, nil
+	}
+
+	s, err := NewExpirySweeper(idx, time.Minute, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := s.Sweep()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(idx.batches) != 1 {
+		t.Fatalf("expected one batch of 2 deletes, got %d deletes in %d batches", n, len(idx.batches))
+	}
+	for id, doc := range idx.batches[0].IndexOps {
+		if doc != nil {
+			t.Errorf("expected delete of '%s'", id)
+		}
+	}
+}
+
+func TestExpirySweeperLifecycle(t *testing.T) {
+	if _, err := NewExpirySweeper(&testExpiryIndex{}, 0, 1); err == nil {
+		t.Errorf("expected error for zero interval")
+	}
+
+	s, err := NewExpirySweeper(&testExpiryIndex{}, time.Hour, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// must not block without a prior Start
+	s.Close()
+	s.Close()
+	s.Start()
+
+	s, err = NewExpirySweeper(&testExpiryIndex{}, time.Hour, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	s.Start()
+	s.Close()
+	s.Close()
+}
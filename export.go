@@ -0,0 +1,524 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ExportFormat identifies the encoding of an exported index stream.
+type ExportFormat int
+
+const (
+	// ExportNDJSON encodes one JSON ExportRecord per line.
+	ExportNDJSON ExportFormat = iota
+	// ExportBinary encodes length-prefixed records after a short header.
+	ExportBinary
+)
+
+const (
+	ExportRecordDocument = "doc"
+	ExportRecordInternal = "internal"
+)
+
+// DefaultImportBatchSize is the number of records per batch used by Import
+// when ImportOptions.BatchSize is not set, see ImportOptions.BatchSize.
+const DefaultImportBatchSize = 1000
+
+var exportBinaryMagic = []byte("BLVX\x01")
+
+var ErrInvalidExportStream = errors.New("invalid export stream")
+
+// ExportedField is the stored content of a document field.
+type ExportedField struct {
+	Name           string               `json:"name"`
+	Type           byte                 `json:"type"`
+	ArrayPositions []uint64             `json:"array_positions,omitempty"`
+	Options        FieldIndexingOptions `json:"options"`
+	Value          []byte               `json:"value"`
+}
+
+// ExportRecord is a single entry of an exported index stream, holding
+// either a document with its stored fields or an internal key-value pair.
+type ExportRecord struct {
+	Kind   string           `json:"kind"`
+	ID     string           `json:"id,omitempty"`
+	Fields []*ExportedField `json:"fields,omitempty"`
+	Key    []byte           `json:"key,omitempty"`
+	Value  []byte           `json:"value,omitempty"`
+}
+
+// ExportProgress reports the number of records exported or imported so far.
+type ExportProgress struct {
+	Documents    uint64
+	InternalKeys uint64
+}
+
+type ExportOptions struct {
+	Format ExportFormat
+	// Progress, when set, is invoked after every exported record.
+	Progress func(ExportProgress)
+}
+
+// Export writes every document of the reader, in DocIDReaderAll order and
+// with its stored fields, followed by every internal key-value pair when
+// the reader implements IndexReaderInternal.
+func Export(reader IndexReader, w io.Writer, opts ExportOptions) (ExportProgress, error) {
+	var progress ExportProgress
+	enc, err := newExportEncoder(w, opts.Format)
+	if err != nil {
+		return progress, err
+	}
+
+	err = exportDocuments(reader, func(rec *ExportRecord) error {
+		if err := enc.encode(rec); err != nil {
+			return err
+		}
+		progress.Documents++
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+		return nil
+	})
+	if err != nil {
+		return progress, err
+	}
+
+	if ri, ok := reader.(IndexReaderInternal); ok {
+		err = exportInternal(ri, func(rec *ExportRecord) error {
+			if err := enc.encode(rec); err != nil {
+				return err
+			}
+			progress.InternalKeys++
+			if opts.Progress != nil {
+				opts.Progress(progress)
+			}
+			return nil
+		})
+		if err != nil {
+			return progress, err
+		}
+	}
+
+	return progress, enc.flush()
+}
+
+func exportDocuments(reader IndexReader, emit func(*ExportRecord) error) (err error) {
+	dr, err := reader.DocIDReaderAll()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := dr.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	for {
+		internalID, err := dr.Next()
+		if err != nil {
+			return err
+		}
+		if internalID == nil {
+			return nil
+		}
+		id, err := reader.ExternalID(internalID)
+		if err != nil {
+			return err
+		}
+		doc, err := reader.Document(id)
+		if err != nil {
+			return err
+		}
+		if doc == nil {
+			continue
+		}
+		rec := &ExportRecord{Kind: ExportRecordDocument, ID: id}
+		doc.VisitFields(func(f Field) {
+			rec.Fields = append(rec.Fields, &ExportedField{
+				Name:           f.Name(),
+				Type:           f.EncodedFieldType(),
+				ArrayPositions: f.ArrayPositions(),
+				Options:        f.Options(),
+				Value:          f.Value(),
+			})
+		})
+		if err := emit(rec); err != nil {
+			return err
+		}
+	}
+}
+
+func exportInternal(reader IndexReaderInternal, emit func(*ExportRecord) error) (err error) {
+	it, err := reader.InternalIterator(nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := it.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	for {
+		key, val, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			return nil
+		}
+		err = emit(&ExportRecord{
+			Kind: ExportRecordInternal,
+			Key:  append([]byte(nil), key...),
+			// an empty value must not be mistaken for a delete on import
+			Value: append([]byte{}, val...),
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type ImportOptions struct {
+	Format ExportFormat
+	// BatchSize is the number of records per batch, counting documents
+	// and internal key-value pairs alike, defaulting to
+	// DefaultImportBatchSize.
+	BatchSize int
+	// Builder converts an exported document back into a Document. It
+	// defaults to NewStoredDocument, which restores the stored fields only;
+	// supply a builder backed by the index mapping to make the imported
+	// content searchable again.
+	Builder func(id string, fields []*ExportedField) (Document, error)
+	// Progress, when set, is invoked after every executed batch.
+	Progress func(ExportProgress)
+}
+
+// Import replays an exported index stream into the index, preserving the
+// document ids and internal key-value pairs.
+func Import(idx Index, r io.Reader, opts ImportOptions) (ExportProgress, error) {
+	var progress ExportProgress
+	dec, err := newExportDecoder(r, opts.Format)
+	if err != nil {
+		return progress, err
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+	builder := opts.Builder
+	if builder == nil {
+		builder = func(id string, fields []*ExportedField) (Document, error) {
+			return NewStoredDocument(id, fields), nil
+		}
+	}
+
+	var pending ExportProgress
+	b := NewBatch()
+	execute := func() error {
+		if len(b.IndexOps) == 0 && len(b.InternalOps) == 0 {
+			return nil
+		}
+		if err := idx.Batch(b); err != nil {
+			return err
+		}
+		progress.Documents += pending.Documents
+		progress.InternalKeys += pending.InternalKeys
+		pending = ExportProgress{}
+		b = NewBatch()
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+		return nil
+	}
+
+	for {
+		rec, err := dec.decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return progress, err
+		}
+		switch rec.Kind {
+		case ExportRecordDocument:
+			doc, err := builder(rec.ID, rec.Fields)
+			if err != nil {
+				return progress, err
+			}
+			b.Update(doc)
+			pending.Documents++
+		case ExportRecordInternal:
+			// empty values are omitted from NDJSON, but present nonetheless
+			val := rec.Value
+			if val == nil {
+				val = []byte{}
+			}
+			b.SetInternal(rec.Key, val)
+			pending.InternalKeys++
+		default:
+			return progress, fmt.Errorf("%w: unknown record kind '%s'", ErrInvalidExportStream, rec.Kind)
+		}
+		if len(b.IndexOps)+len(b.InternalOps) >= batchSize {
+			if err := execute(); err != nil {
+				return progress, err
+			}
+		}
+	}
+
+	return progress, execute()
+}
+
+// -----------------------------------------------------------------------------
+
+type exportEncoder interface {
+	encode(*ExportRecord) error
+	flush() error
+}
+
+func newExportEncoder(w io.Writer, format ExportFormat) (exportEncoder, error) {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case ExportNDJSON:
+		return &jsonExportEncoder{w: bw, enc: json.NewEncoder(bw)}, nil
+	case ExportBinary:
+		if _, err := bw.Write(exportBinaryMagic); err != nil {
+			return nil, err
+		}
+		return &binaryExportEncoder{w: bw}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %d", format)
+}
+
+type jsonExportEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (e *jsonExportEncoder) encode(rec *ExportRecord) error {
+	return e.enc.Encode(rec)
+}
+
+func (e *jsonExportEncoder) flush() error {
+	return e.w.Flush()
+}
+
+// binaryExportEncoder writes a document record as
+//
+//	'd' id numFields [name type options numArrayPositions arrayPositions... value]...
+//
+// and an internal record as
+//
+//	'i' key value
+//
+// where strings and byte slices are prefixed with their uvarint length, and
+// numbers are encoded as uvarints.
+type binaryExportEncoder struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (e *binaryExportEncoder) encode(rec *ExportRecord) error {
+	switch rec.Kind {
+	case ExportRecordDocument:
+		_ = e.w.WriteByte('d')
+		e.writeBytes([]byte(rec.ID))
+		e.writeUvarint(uint64(len(rec.Fields)))
+		for _, f := range rec.Fields {
+			e.writeBytes([]byte(f.Name))
+			_ = e.w.WriteByte(f.Type)
+			e.writeUvarint(uint64(f.Options))
+			e.writeUvarint(uint64(len(f.ArrayPositions)))
+			for _, ap := range f.ArrayPositions {
+				e.writeUvarint(ap)
+			}
+			e.writeBytes(f.Value)
+		}
+	case ExportRecordInternal:
+		_ = e.w.WriteByte('i')
+		e.writeBytes(rec.Key)
+		e.writeBytes(rec.Value)
+	default:
+		return fmt.Errorf("unknown record kind '%s'", rec.Kind)
+	}
+	// bufio.Writer errors are sticky, so checking the last write suffices
+	_, err := e.w.Write(nil)
+	return err
+}
+
+func (e *binaryExportEncoder) writeUvarint(v uint64) {
+	n := binary.PutUvarint(e.buf[:], v)
+	_, _ = e.w.Write(e.buf[:n])
+}
+
+func (e *binaryExportEncoder) writeBytes(b []byte) {
+	e.writeUvarint(uint64(len(b)))
+	_, _ = e.w.Write(b)
+}
+
+func (e *binaryExportEncoder) flush() error {
+	return e.w.Flush()
+}
+
+type exportDecoder interface {
+	// decode returns io.EOF at the end of the stream.
+	decode() (*ExportRecord, error)
+}
+
+func newExportDecoder(r io.Reader, format ExportFormat) (exportDecoder, error) {
+	br := bufio.NewReader(r)
+	switch format {
+	case ExportNDJSON:
+		return &jsonExportDecoder{dec: json.NewDecoder(br)}, nil
+	case ExportBinary:
+		magic := make([]byte, len(exportBinaryMagic))
+		if _, err := io.ReadFull(br, magic); err != nil || !bytes.Equal(magic, exportBinaryMagic) {
+			return nil, fmt.Errorf("%w: bad header", ErrInvalidExportStream)
+		}
+		return &binaryExportDecoder{r: br}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %d", format)
+}
+
+type jsonExportDecoder struct {
+	dec *json.Decoder
+}
+
+func (d *jsonExportDecoder) decode() (*ExportRecord, error) {
+	var rec ExportRecord
+	if err := d.dec.Decode(&rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+type binaryExportDecoder struct {
+	r *bufio.Reader
+}
+
+func (d *binaryExportDecoder) decode() (*ExportRecord, error) {
+	kind, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case 'd':
+		return d.decodeDocument()
+	case 'i':
+		key, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		val, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		return &ExportRecord{Kind: ExportRecordInternal, Key: key, Value: val}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown record kind '%c'", ErrInvalidExportStream, kind)
+}
+
+func (d *binaryExportDecoder) decodeDocument() (*ExportRecord, error) {
+	id, err := d.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	numFields, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	rec := &ExportRecord{Kind: ExportRecordDocument, ID: string(id)}
+	for i := uint64(0); i < numFields; i++ {
+		name, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		typ, err := d.r.ReadByte()
+		if err != nil {
+			return nil, d.truncated(err)
+		}
+		options, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		numArrayPositions, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		var arrayPositions []uint64
+		for j := uint64(0); j < numArrayPositions; j++ {
+			ap, err := d.readUvarint()
+			if err != nil {
+				return nil, err
+			}
+			arrayPositions = append(arrayPositions, ap)
+		}
+		value, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		rec.Fields = append(rec.Fields, &ExportedField{
+			Name:           string(name),
+			Type:           typ,
+			ArrayPositions: arrayPositions,
+			Options:        FieldIndexingOptions(options),
+			Value:          value,
+		})
+	}
+	return rec, nil
+}
+
+func (d *binaryExportDecoder) readUvarint() (uint64, error) {
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return 0, d.truncated(err)
+	}
+	return v, nil
+}
+
+func (d *binaryExportDecoder) readBytes() ([]byte, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > math.MaxInt64 {
+		return nil, fmt.Errorf("%w: invalid length %d", ErrInvalidExportStream, n)
+	}
+	// grow the buffer as data is read, rather than trusting the length
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+		return nil, d.truncated(err)
+	}
+	if buf.Len() == 0 {
+		return []byte{}, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// truncated reports an end of stream in the middle of a record.
+func (d *binaryExportDecoder) truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: truncated record", ErrInvalidExportStream)
+	}
+	return err
+}
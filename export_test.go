@@ -0,0 +1,142 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExportImport(t *testing.T) {
+	reader := &testIndexReader{
+		docs: []*testReaderDoc{
+			{id: "a", fields: []Field{
+				&testField{name: "_id", value: []byte("a"), options: IndexField | StoreField},
+				&testField{name: "name", value: []byte("marty"), options: IndexField | StoreField},
+			}},
+			{id: "b", fields: []Field{
+				&testField{name: "name", value: []byte("doc"), options: StoreField},
+				&testField{name: "city", value: []byte("hill valley"), options: StoreField},
+			}},
+			{id: "c"},
+		},
+		internal: map[string][]byte{
+			"_mapping": []byte("{}"),
+			"offset":   {0x00, 0xff},
+			"empty":    {},
+		},
+	}
+
+	for _, format := range []ExportFormat{ExportNDJSON, ExportBinary} {
+		t.Run(fmt.Sprintf("format-%d", format), func(t *testing.T) {
+			var buf bytes.Buffer
+			var exportCalls int
+			progress, err := Export(reader, &buf, ExportOptions{
+				Format:   format,
+				Progress: func(ExportProgress) { exportCalls++ },
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if progress.Documents != 3 || progress.InternalKeys != 3 || exportCalls != 6 {
+				t.Fatalf("unexpected export progress %+v after %d calls", progress, exportCalls)
+			}
+
+			idx := &testIndex{}
+			progress, err = Import(idx, &buf, ImportOptions{Format: format, BatchSize: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if progress.Documents != 3 || progress.InternalKeys != 3 {
+				t.Fatalf("unexpected import progress %+v", progress)
+			}
+			if len(idx.batches) != 3 {
+				t.Fatalf("expected 3 batches, got %d", len(idx.batches))
+			}
+
+			docs := map[string]Document{}
+			for _, b := range idx.batches {
+				for id, doc := range b.IndexOps {
+					docs[id] = doc
+				}
+			}
+			if len(docs) != 3 {
+				t.Fatalf("expected 3 documents, got %d", len(docs))
+			}
+			var fields []string
+			docs["b"].VisitFields(func(f Field) {
+				fields = append(fields, f.Name()+"="+string(f.Value()))
+				if f.Options() != StoreField {
+					t.Errorf("expected stored-only field, got %s", f.Options())
+				}
+			})
+			if len(fields) != 2 || fields[0] != "name=doc" || fields[1] != "city=hill valley" {
+				t.Errorf("unexpected fields %v", fields)
+			}
+
+			var numFields int
+			docs["a"].AddIDField()
+			docs["a"].VisitFields(func(f Field) {
+				numFields++
+				if f.Name() == IDFieldName {
+					f.Analyze()
+					if _, ok := f.AnalyzedTokenFrequencies()["a"]; !ok {
+						t.Errorf("expected the id field to be analyzed into the id")
+					}
+				}
+			})
+			if numFields != 2 {
+				t.Errorf("expected name and id fields, got %d fields", numFields)
+			}
+
+			if !bytes.Equal(idx.internal["offset"], []byte{0x00, 0xff}) {
+				t.Errorf("expected internal keys to be imported, got %v", idx.internal)
+			}
+			if val, ok := idx.internal["empty"]; !ok || val == nil || len(val) != 0 {
+				t.Errorf("expected empty internal value to be imported, got %v", idx.internal)
+			}
+		})
+	}
+}
+
+func TestImportTruncatedBinary(t *testing.T) {
+	reader := &testIndexReader{
+		docs: []*testReaderDoc{
+			{id: "a", fields: []Field{&testField{name: "name", value: []byte("marty"), options: StoreField}}},
+		},
+	}
+	var buf bytes.Buffer
+	if _, err := Export(reader, &buf, ExportOptions{Format: ExportBinary}); err != nil {
+		t.Fatal(err)
+	}
+	truncated := bytes.NewReader(buf.Bytes()[:buf.Len()-2])
+	_, err := Import(&testIndex{}, truncated, ImportOptions{Format: ExportBinary})
+	if !errors.Is(err, ErrInvalidExportStream) {
+		t.Errorf("expected ErrInvalidExportStream, got %v", err)
+	}
+}
+
+func TestImportOversizedBinary(t *testing.T) {
+	for _, n := range []uint64{1 << 62, 1 << 63} {
+		stream := append([]byte("BLVX\x01d"), binary.AppendUvarint(nil, n)...)
+		_, err := Import(&testIndex{}, bytes.NewReader(stream), ImportOptions{Format: ExportBinary})
+		if !errors.Is(err, ErrInvalidExportStream) {
+			t.Errorf("expected ErrInvalidExportStream for length %d, got %v", n, err)
+		}
+	}
+}
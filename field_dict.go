@@ -0,0 +1,235 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import "bytes"
+
+// SeekableFieldDict is a FieldDict which can be repositioned. Terms are
+// enumerated in byte lexicographic order, ascending or descending depending
+// on how the dictionary was opened.
+type SeekableFieldDict interface {
+	FieldDict
+
+	// Seek repositions the dictionary, so that the following call to Next
+	// returns the first term greater than or equal to term, or less than
+	// or equal to term for a descending dictionary. A nil term repositions
+	// the dictionary at its start.
+	Seek(term []byte) error
+
+	// Descending returns true if terms are enumerated in descending order.
+	Descending() bool
+}
+
+// IndexReaderSeekableFieldDict provides functionality to browse field
+// dictionaries in both directions.
+type IndexReaderSeekableFieldDict interface {
+	// SeekableFieldDict returns a SeekableFieldDict over all the terms of
+	// the given field, positioned at its start.
+	SeekableFieldDict(field string, descending bool) (SeekableFieldDict, error)
+}
+
+// MaxTerm returns the greatest term in the dictionary of the given field, or
+// nil if the dictionary is empty. It reads a single entry off a descending
+// SeekableFieldDict when the reader supports it, and scans the whole
+// dictionary otherwise.
+func MaxTerm(reader IndexReader, field string) (rv *DictEntry, err error) {
+	var fd FieldDict
+	if sr, ok := reader.(IndexReaderSeekableFieldDict); ok {
+		fd, err = sr.SeekableFieldDict(field, true)
+	} else {
+		fd, err = reader.FieldDict(field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := fd.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if sfd, ok := fd.(SeekableFieldDict); ok && sfd.Descending() {
+		entry, err := sfd.Next()
+		if err != nil || entry == nil {
+			return nil, err
+		}
+		last := *entry
+		return &last, nil
+	}
+	for {
+		entry, err := fd.Next()
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return rv, nil
+		}
+		// entries may be reused by the following call to Next
+		last := *entry
+		rv = &last
+	}
+}
+
+// TermRange describes a range of terms. A nil Start or End leaves that side
+// of the range unbounded, in which case the corresponding inclusive flag is
+// ignored.
+type TermRange struct {
+	Start          []byte
+	End            []byte
+	StartInclusive bool
+	EndInclusive   bool
+}
+
+// Contains returns true if the term lies within the range.
+func (r *TermRange) Contains(term []byte) bool {
+	return !r.before(term) && !r.after(term)
+}
+
+// before returns true if the term sorts before the start of the range.
+func (r *TermRange) before(term []byte) bool {
+	if r.Start == nil {
+		return false
+	}
+	c := bytes.Compare(term, r.Start)
+	return c < 0 || (c == 0 && !r.StartInclusive)
+}
+
+// after returns true if the term sorts after the end of the range.
+func (r *TermRange) after(term []byte) bool {
+	if r.End == nil {
+		return false
+	}
+	c := bytes.Compare(term, r.End)
+	return c > 0 || (c == 0 && !r.EndInclusive)
+}
+
+// IndexReaderFieldDictRange provides functionality to enumerate field
+// dictionaries over ranges with exclusive or open bounds.
+type IndexReaderFieldDictRange interface {
+	// FieldDictRangeOpts returns a FieldDict for the terms of the given
+	// field within the range.
+	FieldDictRangeOpts(field string, r TermRange) (FieldDict, error)
+}
+
+// FieldDictRangeOpts returns a FieldDict for the terms of the given field
+// within the range. It relies on IndexReaderFieldDictRange when the reader
+// supports it. Otherwise, bounded ranges are served by FieldDictRange, left
+// unbounded ranges by FieldDict, and right unbounded ranges by seeking a
+// SeekableFieldDict if available, or by FieldDict; exclusive bounds and
+// terms outside the range are filtered out.
+func FieldDictRangeOpts(reader IndexReader, field string, r TermRange) (FieldDict, error) {
+	if rr, ok := reader.(IndexReaderFieldDictRange); ok {
+		return rr.FieldDictRangeOpts(field, r)
+	}
+
+	var fd FieldDict
+	var err error
+	switch {
+	case r.Start != nil && r.End != nil:
+		if bytes.Compare(r.Start, r.End) > 0 {
+			return &emptyFieldDict{}, nil
+		}
+		fd, err = reader.FieldDictRange(field, r.Start, r.End)
+	case r.Start != nil:
+		fd, err = seekFieldDict(reader, field, r.Start)
+	default:
+		fd, err = reader.FieldDict(field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rangeFieldDict{FieldDict: fd, r: r}, nil
+}
+
+// seekFieldDict returns the dictionary of the field positioned at the given
+// term if the reader supports seeking, or at its start otherwise.
+func seekFieldDict(reader IndexReader, field string, term []byte) (FieldDict, error) {
+	sr, ok := reader.(IndexReaderSeekableFieldDict)
+	if !ok {
+		return reader.FieldDict(field)
+	}
+	sfd, err := sr.SeekableFieldDict(field, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := sfd.Seek(term); err != nil {
+		_ = sfd.Close()
+		return nil, err
+	}
+	return sfd, nil
+}
+
+// rangeFieldDict filters an ascending FieldDict down to the terms within a
+// range, stopping at the first term past its end.
+type rangeFieldDict struct {
+	FieldDict
+	r    TermRange
+	done bool
+}
+
+func (d *rangeFieldDict) Next() (*DictEntry, error) {
+	for !d.done {
+		entry, err := d.FieldDict.Next()
+		if err != nil || entry == nil {
+			return nil, err
+		}
+		term := []byte(entry.Term)
+		if d.r.before(term) {
+			continue
+		}
+		if d.r.after(term) {
+			d.done = true
+			break
+		}
+		return entry, nil
+	}
+	return nil, nil
+}
+
+// emptyFieldDict is a FieldDict without any terms.
+type emptyFieldDict struct{}
+
+func (d *emptyFieldDict) Next() (*DictEntry, error) { return nil, nil }
+func (d *emptyFieldDict) Close() error              { return nil }
+func (d *emptyFieldDict) Cardinality() int          { return 0 }
+func (d *emptyFieldDict) BytesRead() uint64         { return 0 }
+
+// filteredFieldDict passes the entries of a FieldDict through a filter,
+// which drops an entry by returning nil or can substitute it.
+type filteredFieldDict struct {
+	FieldDict
+	filter func(*DictEntry) *DictEntry
+}
+
+func (d *filteredFieldDict) Next() (*DictEntry, error) {
+	for {
+		entry, err := d.FieldDict.Next()
+		if err != nil || entry == nil {
+			return nil, err
+		}
+		if entry = d.filter(entry); entry != nil {
+			return entry, nil
+		}
+	}
+}
+
+// fieldDictOrPrefix returns the dictionary of the field restricted to the
+// terms starting with prefix, if any.
+func fieldDictOrPrefix(reader IndexReader, field string, prefix string) (FieldDict, error) {
+	if prefix == "" {
+		return reader.FieldDict(field)
+	}
+	return reader.FieldDictPrefix(field, []byte(prefix))
+}
@@ -0,0 +1,109 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestDictReader(field string, terms ...string) *testIndexReader {
+	rv := &testIndexReader{}
+	for _, term := range terms {
+		rv.docs = append(rv.docs, &testReaderDoc{
+			id:    term,
+			terms: map[string][]string{field: {term}},
+		})
+	}
+	return rv
+}
+
+func TestMaxTerm(t *testing.T) {
+	reader := newTestDictReader("name", "bob", "alice", "dave", "carol")
+	for _, r := range []IndexReader{reader, &testPlainIndexReader{reader}} {
+		entry, err := MaxTerm(r, "name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry == nil || entry.Term != "dave" {
+			t.Errorf("expected 'dave', got %v", entry)
+		}
+		entry, err = MaxTerm(r, "missing")
+		if err != nil || entry != nil {
+			t.Errorf("expected no term, got %v, %v", entry, err)
+		}
+	}
+}
+
+func TestFieldDictRangeOpts(t *testing.T) {
+	reader := newTestDictReader("name", "alice", "bob", "carol", "dave", "erin")
+	tests := []struct {
+		r        TermRange
+		expected []string
+	}{
+		{
+			r:        TermRange{},
+			expected: []string{"alice", "bob", "carol", "dave", "erin"},
+		},
+		{
+			r:        TermRange{Start: []byte("bob"), End: []byte("dave"), StartInclusive: true, EndInclusive: true},
+			expected: []string{"bob", "carol", "dave"},
+		},
+		{
+			r:        TermRange{Start: []byte("bob"), End: []byte("dave")},
+			expected: []string{"carol"},
+		},
+		{
+			r:        TermRange{Start: []byte("bob")},
+			expected: []string{"carol", "dave", "erin"},
+		},
+		{
+			r:        TermRange{Start: []byte("bz"), StartInclusive: true},
+			expected: []string{"carol", "dave", "erin"},
+		},
+		{
+			r:        TermRange{End: []byte("carol")},
+			expected: []string{"alice", "bob"},
+		},
+		{
+			r:        TermRange{End: []byte("carol"), EndInclusive: true},
+			expected: []string{"alice", "bob", "carol"},
+		},
+		{
+			r: TermRange{Start: []byte("dave"), End: []byte("bob"), StartInclusive: true, EndInclusive: true},
+		},
+		{
+			r: TermRange{Start: []byte("carol"), End: []byte("carol"), StartInclusive: true},
+		},
+	}
+	for _, r := range []IndexReader{reader, &testPlainIndexReader{reader}} {
+		for i, test := range tests {
+			fd, err := FieldDictRangeOpts(r, "name", test.r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for entry, err := range Dict(fd) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, entry.Term)
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("test %d (%T): expected %v, got %v", i, r, test.expected, got)
+			}
+		}
+	}
+}
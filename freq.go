@@ -29,18 +29,30 @@ func init() {
 // TokenLocation represents one occurrence of a term at a particular location in
 // a field. Start, End and Position have the same meaning as in analysis.Token.
 // Field and ArrayPositions identify the field value in the source document.
-// See document.Field for details.
+// See document.Field for details. Payload is optional data attached to the
+// occurrence by analysis, indexed when the field includes payloads.
+// PositionLength is the number of positions the token spans in a token
+// graph, as produced by multi-word synonyms or decompounding; 0 stands for
+// a single position.
 type TokenLocation struct {
 	Field          string
 	ArrayPositions []uint64
 	Start          int
 	End            int
 	Position       int
+	Payload        []byte
+	PositionLength int
+}
+
+// EndPosition returns the position following the token in the token graph.
+func (tl *TokenLocation) EndPosition() int {
+	return tl.Position + max(tl.PositionLength, 1)
 }
 
 func (tl *TokenLocation) Size() int {
 	rv := reflectStaticSizeTokenLocation
 	rv += len(tl.ArrayPositions) * sizeOfUint64
+	rv += len(tl.Payload)
 	return rv
 }
 
@@ -83,6 +95,9 @@ func (tfs TokenFrequencies) Size() int {
 	return rv
 }
 
+// MergeAll merges the token frequencies of another field, moving its
+// locations, along with their payloads and position lengths, under
+// remoteField.
 func (tfs TokenFrequencies) MergeAll(remoteField string, other TokenFrequencies) {
 	// walk the new token frequencies
 	for tfk, tf := range other {
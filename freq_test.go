@@ -132,3 +132,115 @@ func TestTokenFrequenciesMergeAllLeftEmpty(t *testing.T) {
 		t.Errorf("expected %#v, got %#v", expectedResult, tf1)
 	}
 }
+
+func TestTokenFrequenciesMergeAllPayloads(t *testing.T) {
+	tf1 := TokenFrequencies{
+		"water": &TokenFreq{
+			Term: []byte("water"),
+			Locations: []*TokenLocation{
+				{
+					Position: 1,
+					Start:    0,
+					End:      5,
+					Payload:  []byte("NN"),
+				},
+			},
+		},
+	}
+	tf2 := TokenFrequencies{
+		"water": &TokenFreq{
+			Term: []byte("water"),
+			Locations: []*TokenLocation{
+				{
+					Position: 2,
+					Start:    6,
+					End:      11,
+					Payload:  []byte("VB"),
+				},
+			},
+		},
+		"fall": &TokenFreq{
+			Term: []byte("fall"),
+			Locations: []*TokenLocation{
+				{
+					Position: 3,
+					Start:    12,
+					End:      16,
+					Payload:  []byte("NN"),
+				},
+			},
+		},
+	}
+	expectedResult := TokenFrequencies{
+		"water": &TokenFreq{
+			Term: []byte("water"),
+			Locations: []*TokenLocation{
+				{
+					Position: 1,
+					Start:    0,
+					End:      5,
+					Payload:  []byte("NN"),
+				},
+				{
+					Field:    "tf2",
+					Position: 2,
+					Start:    6,
+					End:      11,
+					Payload:  []byte("VB"),
+				},
+			},
+		},
+		"fall": &TokenFreq{
+			Term: []byte("fall"),
+			Locations: []*TokenLocation{
+				{
+					Field:    "tf2",
+					Position: 3,
+					Start:    12,
+					End:      16,
+					Payload:  []byte("NN"),
+				},
+			},
+		},
+	}
+	tf1.MergeAll("tf2", tf2)
+	if !reflect.DeepEqual(tf1, expectedResult) {
+		t.Errorf("expected %#v, got %#v", expectedResult, tf1)
+	}
+}
+
+func TestTokenFrequenciesMergeAllPositionLength(t *testing.T) {
+	// "ny" is a synonym of "new york", spanning its two positions
+	tf1 := TokenFrequencies{}
+	tf2 := TokenFrequencies{
+		"ny": &TokenFreq{
+			Term: []byte("ny"),
+			Locations: []*TokenLocation{
+				{
+					Position:       1,
+					Start:          0,
+					End:            8,
+					PositionLength: 2,
+				},
+			},
+		},
+		"york": &TokenFreq{
+			Term: []byte("york"),
+			Locations: []*TokenLocation{
+				{
+					Position: 2,
+					Start:    4,
+					End:      8,
+				},
+			},
+		},
+	}
+	tf1.MergeAll("tf2", tf2)
+	ny := tf1["ny"].Locations[0]
+	if ny.Field != "tf2" || ny.PositionLength != 2 || ny.EndPosition() != 3 {
+		t.Errorf("expected position length to be preserved, got %#v", ny)
+	}
+	if york := tf1["york"].Locations[0]; york.EndPosition() != 3 {
+		t.Errorf("expected single position token to end at 3, got %d", york.EndPosition())
+	}
+}
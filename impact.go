@@ -0,0 +1,78 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import "math"
+
+// ImpactBlock describes a block of postings by upper bounds of the values
+// scoring depends on, so that query engines can skip blocks which cannot
+// produce competitive scores, as in block-max WAND and MaxScore.
+type ImpactBlock struct {
+	// LastID is the id of the last document of the block, nil when the
+	// block extends to the end of the postings.
+	LastID IndexInternalID
+
+	// MaxFreq is the greatest term frequency within the block.
+	MaxFreq uint64
+
+	// MaxNorm is the greatest TermFieldDoc.Norm within the block, that of
+	// its shortest field, which is the most favourable to scoring.
+	MaxNorm float64
+}
+
+// FollowingID returns the id following the block, to pass to AdvanceShallow
+// or Advance in order to skip it, or nil for the last block.
+func (b *ImpactBlock) FollowingID() IndexInternalID {
+	if b.LastID == nil || b.LastID.Value() == math.MaxUint64 {
+		return nil
+	}
+	return NewIndexInternalID(nil, b.LastID.Value()+1)
+}
+
+// ImpactTermFieldReader is an optional interface for TermFieldReaders which
+// keep per-block score upper bounds alongside their postings.
+type ImpactTermFieldReader interface {
+	TermFieldReader
+
+	// AdvanceShallow returns the block holding the first posting whose
+	// document id is greater than or equal to ID, without decoding the
+	// postings nor moving the position of Next and Advance. It returns nil
+	// when no such block exists.
+	AdvanceShallow(ID IndexInternalID) (*ImpactBlock, error)
+}
+
+// ImpactsOf returns the TermFieldReader as an ImpactTermFieldReader. A
+// reader which keeps no impacts is wrapped so that it reports a single
+// unbounded block, which can never be skipped.
+func ImpactsOf(tfr TermFieldReader) ImpactTermFieldReader {
+	if itfr, ok := tfr.(ImpactTermFieldReader); ok {
+		return itfr
+	}
+	return &unboundedImpactTermFieldReader{TermFieldReader: tfr}
+}
+
+var unboundedImpactBlock = ImpactBlock{
+	MaxFreq: math.MaxUint64,
+	MaxNorm: math.Inf(1),
+}
+
+type unboundedImpactTermFieldReader struct {
+	TermFieldReader
+}
+
+func (r *unboundedImpactTermFieldReader) AdvanceShallow(ID IndexInternalID) (*ImpactBlock, error) {
+	rv := unboundedImpactBlock
+	return &rv, nil
+}
@@ -0,0 +1,97 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"math"
+	"testing"
+)
+
+// testImpactTermFieldReader groups the postings of a testTermFieldReader in
+// blocks of blockSize.
+type testImpactTermFieldReader struct {
+	testTermFieldReader
+	blockSize int
+}
+
+func (t *testImpactTermFieldReader) AdvanceShallow(ID IndexInternalID) (*ImpactBlock, error) {
+	for start := 0; start < len(t.ids); start += t.blockSize {
+		end := min(start+t.blockSize, len(t.ids))
+		if t.ids[end-1] < ID.Value() {
+			continue
+		}
+		rv := &ImpactBlock{MaxNorm: 1}
+		for _, freq := range t.freqs[start:end] {
+			rv.MaxFreq = max(rv.MaxFreq, freq)
+		}
+		if end < len(t.ids) {
+			rv.LastID = NewIndexInternalID(nil, t.ids[end-1])
+		}
+		return rv, nil
+	}
+	return nil, nil
+}
+
+func TestImpactsOf(t *testing.T) {
+	tfr := &testImpactTermFieldReader{
+		testTermFieldReader: testTermFieldReader{
+			ids:   []uint64{1, 2, 5, 7, 8, 9},
+			freqs: []uint64{1, 1, 1, 4, 1, 1},
+		},
+		blockSize: 2,
+	}
+	itfr := ImpactsOf(tfr)
+
+	// collect the postings of the blocks with a frequency of at least 2
+	var matches []uint64
+	id := NewIndexInternalID(nil, 0)
+	for id != nil {
+		block, err := itfr.AdvanceShallow(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if block == nil {
+			break
+		}
+		if block.MaxFreq >= 2 {
+			tfd, err := itfr.Advance(id, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for tfd != nil && (block.LastID == nil || tfd.ID.Compare(block.LastID) <= 0) {
+				matches = append(matches, tfd.ID.Value())
+				if tfd, err = itfr.Next(nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		id = block.FollowingID()
+	}
+	if len(matches) != 2 || matches[0] != 5 || matches[1] != 7 {
+		t.Errorf("expected postings of the second block, got %v", matches)
+	}
+
+	plain := ImpactsOf(&tfr.testTermFieldReader)
+	block, err := plain.AdvanceShallow(NewIndexInternalID(nil, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.LastID != nil || block.MaxFreq != math.MaxUint64 || !math.IsInf(block.MaxNorm, 1) {
+		t.Errorf("expected a single unbounded block, got %+v", block)
+	}
+	if block.FollowingID() != nil {
+		t.Errorf("expected no block following the last one")
+	}
+}
@@ -98,7 +98,8 @@ type IndexReader interface {
 
 	FieldDict(field string) (FieldDict, error)
 
-	// FieldDictRange is currently defined to include the start and end terms
+	// FieldDictRange is currently defined to include the start and end terms,
+	// see FieldDictRangeOpts for exclusive and open-ended bounds
 	FieldDictRange(field string, startTerm []byte, endTerm []byte) (FieldDict, error)
 	FieldDictPrefix(field string, termPrefix []byte) (FieldDict, error)
 
@@ -169,6 +170,26 @@ type IndexReaderFuzzy interface {
 	FieldDictFuzzyAutomaton(field string, term string, fuzziness int, prefix string) (FieldDict, FuzzyAutomaton, error)
 }
 
+// WildcardAutomaton abstracts an automaton built using a wildcard pattern,
+// where '*' matches any sequence of characters and '?' matches exactly one
+// character.
+type WildcardAutomaton interface {
+	// MatchesWildcard returns true if the given string matches the wildcard
+	// pattern used to build the automaton.
+	MatchesWildcard(string) bool
+}
+
+// IndexReaderWildcard provides functionality to work with wildcard-based field dictionaries.
+type IndexReaderWildcard interface {
+	// FieldDictWildcard returns a FieldDict for terms matching the specified wildcard
+	// pattern in the dictionary of the given field.
+	FieldDictWildcard(field string, pattern string) (FieldDict, error)
+
+	// FieldDictWildcardAutomaton returns a FieldDict and a WildcardAutomaton that can be
+	// used to match strings against the wildcard pattern.
+	FieldDictWildcardAutomaton(field string, pattern string) (FieldDict, WildcardAutomaton, error)
+}
+
 type IndexReaderContains interface {
 	FieldDictContains(field string) (FieldDictContains, error)
 }
@@ -187,11 +208,22 @@ type TermFieldVector struct {
 	Pos            uint64
 	Start          uint64
 	End            uint64
+	// Payload is only set for fields including payloads.
+	Payload []byte
+	// PositionLength is the number of positions the term spans in a token
+	// graph, 0 standing for a single position.
+	PositionLength uint64
+}
+
+// EndPos returns the position following the term in the token graph.
+func (tfv *TermFieldVector) EndPos() uint64 {
+	return tfv.Pos + max(tfv.PositionLength, 1)
 }
 
 func (tfv *TermFieldVector) Size() int {
 	return reflectStaticSizeTermFieldVector + sizeOfPtr +
-		len(tfv.Field) + len(tfv.ArrayPositions)*sizeOfUint64
+		len(tfv.Field) + len(tfv.ArrayPositions)*sizeOfUint64 +
+		len(tfv.Payload)
 }
 
 // IndexInternalID is an opaque document identifier internal to the index impl
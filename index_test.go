@@ -0,0 +1,415 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+)
+
+// testReaderDoc is a document held by a testIndexReader. Terms and doc
+// values map field names to the terms of that field.
+type testReaderDoc struct {
+	id        string
+	terms     map[string][]string
+	docValues map[string][]string
+	fields    []Field
+}
+
+// testIndexReader is a minimal in-memory IndexReader. The internal id of a
+// document is its position in docs.
+type testIndexReader struct {
+	docs     []*testReaderDoc
+	internal map[string][]byte
+}
+
+func (r *testIndexReader) TermFieldReader(ctx context.Context, term []byte, field string,
+	includeFreq, includeNorm, includeTermVectors bool) (TermFieldReader, error) {
+	var ids, freqs []uint64
+	for i, d := range r.docs {
+		var freq uint64
+		for _, t := range d.terms[field] {
+			if t == string(term) {
+				freq++
+			}
+		}
+		if freq > 0 {
+			ids = append(ids, uint64(i))
+			freqs = append(freqs, freq)
+		}
+	}
+	return &testTermFieldReader{term: string(term), ids: ids, freqs: freqs}, nil
+}
+
+func (r *testIndexReader) DocIDReaderAll() (DocIDReader, error) {
+	ids := make([]uint64, len(r.docs))
+	for i := range r.docs {
+		ids[i] = uint64(i)
+	}
+	return &testDocIDReader{ids: ids}, nil
+}
+
+func (r *testIndexReader) DocIDReaderOnly(ids []string) (DocIDReader, error) {
+	var rv []uint64
+	for i, d := range r.docs {
+		for _, id := range ids {
+			if d.id == id {
+				rv = append(rv, uint64(i))
+			}
+		}
+	}
+	return &testDocIDReader{ids: rv}, nil
+}
+
+func (r *testIndexReader) dictTerms(field string) []string {
+	counts := map[string]uint64{}
+	for _, d := range r.docs {
+		for _, t := range d.terms[field] {
+			counts[t]++
+		}
+	}
+	terms := make([]string, 0, len(counts))
+	for t := range counts {
+		terms = append(terms, t)
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+func (r *testIndexReader) FieldDict(field string) (FieldDict, error) {
+	return r.FieldDictRange(field, nil, nil)
+}
+
+func (r *testIndexReader) FieldDictRange(field string, startTerm []byte, endTerm []byte) (FieldDict, error) {
+	var entries []*DictEntry
+	for _, t := range r.dictTerms(field) {
+		if startTerm != nil && t < string(startTerm) {
+			continue
+		}
+		if endTerm != nil && t > string(endTerm) {
+			continue
+		}
+		entries = append(entries, &DictEntry{Term: t, Count: r.docFreq(field, t)})
+	}
+	return &testFieldDict{entries: entries}, nil
+}
+
+func (r *testIndexReader) FieldDictPrefix(field string, termPrefix []byte) (FieldDict, error) {
+	var entries []*DictEntry
+	for _, t := range r.dictTerms(field) {
+		if bytes.HasPrefix([]byte(t), termPrefix) {
+			entries = append(entries, &DictEntry{Term: t, Count: r.docFreq(field, t)})
+		}
+	}
+	return &testFieldDict{entries: entries}, nil
+}
+
+func (r *testIndexReader) docFreq(field, term string) uint64 {
+	var rv uint64
+	for _, d := range r.docs {
+		for _, t := range d.terms[field] {
+			if t == term {
+				rv++
+				break
+			}
+		}
+	}
+	return rv
+}
+
+func (r *testIndexReader) Document(id string) (Document, error) {
+	for _, d := range r.docs {
+		if d.id == id {
+			return &testDocument{id: id, fields: d.fields}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *testIndexReader) DocValueReader(fields []string) (DocValueReader, error) {
+	return &testDocValueReader{r: r, fields: fields}, nil
+}
+
+func (r *testIndexReader) Fields() ([]string, error) {
+	seen := map[string]struct{}{}
+	var rv []string
+	for _, d := range r.docs {
+		for f := range d.terms {
+			if _, ok := seen[f]; !ok {
+				seen[f] = struct{}{}
+				rv = append(rv, f)
+			}
+		}
+	}
+	sort.Strings(rv)
+	return rv, nil
+}
+
+func (r *testIndexReader) GetInternal(key []byte) ([]byte, error) {
+	return r.internal[string(key)], nil
+}
+
+func (r *testIndexReader) DocCount() (uint64, error) {
+	return uint64(len(r.docs)), nil
+}
+
+func (r *testIndexReader) ExternalID(id IndexInternalID) (string, error) {
+	n := id.Value()
+	if n >= uint64(len(r.docs)) {
+		return "", fmt.Errorf("unknown internal id %d", n)
+	}
+	return r.docs[n].id, nil
+}
+
+func (r *testIndexReader) InternalID(id string) (IndexInternalID, error) {
+	for i, d := range r.docs {
+		if d.id == id {
+			return NewIndexInternalID(nil, uint64(i)), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *testIndexReader) Close() error {
+	return nil
+}
+
+type testTermFieldReader struct {
+	term  string
+	ids   []uint64
+	freqs []uint64
+	pos   int
+}
+
+func (t *testTermFieldReader) Next(preAlloced *TermFieldDoc) (*TermFieldDoc, error) {
+	if t.pos >= len(t.ids) {
+		return nil, nil
+	}
+	if preAlloced == nil {
+		preAlloced = &TermFieldDoc{}
+	}
+	preAlloced.Term = t.term
+	preAlloced.ID = NewIndexInternalID(preAlloced.ID, t.ids[t.pos])
+	preAlloced.Freq = t.freqs[t.pos]
+	t.pos++
+	return preAlloced, nil
+}
+
+func (t *testTermFieldReader) Advance(ID IndexInternalID, preAlloced *TermFieldDoc) (*TermFieldDoc, error) {
+	for t.pos < len(t.ids) && t.ids[t.pos] < ID.Value() {
+		t.pos++
+	}
+	return t.Next(preAlloced)
+}
+
+func (t *testTermFieldReader) Count() uint64 { return uint64(len(t.ids)) }
+func (t *testTermFieldReader) Close() error  { return nil }
+func (t *testTermFieldReader) Size() int     { return 0 }
+
+type testDocIDReader struct {
+	ids []uint64
+	pos int
+}
+
+func (d *testDocIDReader) Next() (IndexInternalID, error) {
+	if d.pos >= len(d.ids) {
+		return nil, nil
+	}
+	d.pos++
+	return NewIndexInternalID(nil, d.ids[d.pos-1]), nil
+}
+
+func (d *testDocIDReader) Advance(ID IndexInternalID) (IndexInternalID, error) {
+	for d.pos < len(d.ids) && d.ids[d.pos] < ID.Value() {
+		d.pos++
+	}
+	return d.Next()
+}
+
+func (d *testDocIDReader) Size() int    { return 0 }
+func (d *testDocIDReader) Close() error { return nil }
+
+type testDocValueReader struct {
+	r      *testIndexReader
+	fields []string
+}
+
+func (d *testDocValueReader) VisitDocValues(id IndexInternalID, visitor DocValueVisitor) error {
+	doc := d.r.docs[id.Value()]
+	for _, f := range d.fields {
+		for _, v := range doc.docValues[f] {
+			visitor(f, []byte(v))
+		}
+	}
+	return nil
+}
+
+func (d *testDocValueReader) BytesRead() uint64 { return 0 }
+
+type testFieldDict struct {
+	entries []*DictEntry
+	pos     int
+	closed  bool
+}
+
+func (d *testFieldDict) Next() (*DictEntry, error) {
+	if d.pos >= len(d.entries) {
+		return nil, nil
+	}
+	d.pos++
+	return d.entries[d.pos-1], nil
+}
+
+func (d *testFieldDict) Close() error {
+	d.closed = true
+	return nil
+}
+
+func (d *testFieldDict) Cardinality() int  { return len(d.entries) }
+func (d *testFieldDict) BytesRead() uint64 { return 0 }
+
+// testIndex is a minimal Index which records the batches it is given and
+// serves readers from newReader.
+type testIndex struct {
+	batches   []*Batch
+	internal  map[string][]byte
+	newReader func() (IndexReader, error)
+	batchErr  func(b *Batch) error
+}
+
+func (i *testIndex) Open() error  { return nil }
+func (i *testIndex) Close() error { return nil }
+
+func (i *testIndex) Update(doc Document) error {
+	b := NewBatch()
+	b.Update(doc)
+	return i.Batch(b)
+}
+
+func (i *testIndex) Delete(id string) error {
+	b := NewBatch()
+	b.Delete(id)
+	return i.Batch(b)
+}
+
+func (i *testIndex) Batch(b *Batch) error {
+	if i.batchErr != nil {
+		if err := i.batchErr(b); err != nil {
+			return err
+		}
+	}
+	i.batches = append(i.batches, b)
+	for k, v := range b.InternalOps {
+		if i.internal == nil {
+			i.internal = make(map[string][]byte)
+		}
+		if v == nil {
+			delete(i.internal, k)
+		} else {
+			i.internal[k] = v
+		}
+	}
+	return nil
+}
+
+func (i *testIndex) SetInternal(key, val []byte) error {
+	b := NewBatch()
+	b.SetInternal(key, val)
+	return i.Batch(b)
+}
+
+func (i *testIndex) DeleteInternal(key []byte) error {
+	b := NewBatch()
+	b.DeleteInternal(key)
+	return i.Batch(b)
+}
+
+func (i *testIndex) Reader() (IndexReader, error) {
+	return i.newReader()
+}
+
+func (i *testIndex) StatsMap() map[string]interface{} {
+	return nil
+}
+
+func (r *testIndexReader) InternalIterator(prefix []byte) (InternalIterator, error) {
+	var keys []string
+	for k := range r.internal {
+		if bytes.HasPrefix([]byte(k), prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return &testInternalIterator{r: r, keys: keys}, nil
+}
+
+type testInternalIterator struct {
+	r    *testIndexReader
+	keys []string
+	pos  int
+}
+
+func (i *testInternalIterator) Next() ([]byte, []byte, error) {
+	if i.pos >= len(i.keys) {
+		return nil, nil, nil
+	}
+	k := i.keys[i.pos]
+	i.pos++
+	return []byte(k), i.r.internal[k], nil
+}
+
+func (i *testInternalIterator) Close() error { return nil }
+
+func (r *testIndexReader) SeekableFieldDict(field string, descending bool) (SeekableFieldDict, error) {
+	fd, err := r.FieldDict(field)
+	if err != nil {
+		return nil, err
+	}
+	entries := fd.(*testFieldDict).entries
+	if descending {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+	return &testSeekableFieldDict{testFieldDict{entries: entries}, descending}, nil
+}
+
+type testSeekableFieldDict struct {
+	testFieldDict
+	descending bool
+}
+
+func (d *testSeekableFieldDict) Seek(term []byte) error {
+	d.pos = 0
+	for d.pos < len(d.entries) && term != nil {
+		t := d.entries[d.pos].Term
+		if (!d.descending && t >= string(term)) || (d.descending && t <= string(term)) {
+			break
+		}
+		d.pos++
+	}
+	return nil
+}
+
+func (d *testSeekableFieldDict) Descending() bool {
+	return d.descending
+}
+
+// testPlainIndexReader hides the optional interfaces of a testIndexReader.
+type testPlainIndexReader struct {
+	IndexReader
+}
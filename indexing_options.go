@@ -25,6 +25,8 @@ const (
 	SkipDVCompression
 	SkipDVChunking
 	GPU
+	IncludeReversedTerms
+	IncludePayloads
 )
 
 const (
@@ -39,7 +41,7 @@ const DefaultScoringModel = TFIDFScoring
 // Sentinel value used to separate terms in doc values encoding
 const DocValueTermSeparator byte = 0xff
 
-// Supported similarity models
+// Supported similarity models, kept in sync by RegisterScoringModel
 var SupportedScoringModels = map[string]struct{}{
 	BM25Scoring:  {},
 	TFIDFScoring: {},
@@ -77,6 +79,20 @@ func (o FieldIndexingOptions) UseGPU() bool {
 	return o&GPU != 0
 }
 
+// IncludeReversedTerms indicates that the terms of the field are also
+// indexed reversed, under the field named ReversedFieldName(field), so
+// that suffix lookups can be served by prefix walks.
+func (o FieldIndexingOptions) IncludeReversedTerms() bool {
+	return o&IncludeReversedTerms != 0
+}
+
+// IncludePayloads indicates that the payloads of the token locations of the
+// field are indexed. They are kept alongside term vectors, and so require
+// IncludeTermVectors as well.
+func (o FieldIndexingOptions) IncludePayloads() bool {
+	return o&IncludePayloads != 0
+}
+
 func (o FieldIndexingOptions) String() string {
 	rv := ""
 	if o.IsIndexed() {
@@ -124,5 +140,17 @@ func (o FieldIndexingOptions) String() string {
 		}
 		rv += "GPU"
 	}
+	if o.IncludeReversedTerms() {
+		if rv != "" {
+			rv += ", "
+		}
+		rv += "REV"
+	}
+	if o.IncludePayloads() {
+		if rv != "" {
+			rv += ", "
+		}
+		rv += "PAYLOADS"
+	}
 	return rv
 }
@@ -27,6 +27,8 @@ func TestFieldIndexingOptions(t *testing.T) {
 		docValues          bool
 		skipFreqNorm       bool
 		useGPU             bool
+		reverseTerms       bool
+		includePayloads    bool
 	}{
 		{
 			options:            IndexField | StoreField | IncludeTermVectors,
@@ -95,6 +97,17 @@ func TestFieldIndexingOptions(t *testing.T) {
 			skipFreqNorm: true,
 			useGPU:       true,
 		},
+		{
+			options:      IndexField | IncludeReversedTerms,
+			isIndexed:    true,
+			reverseTerms: true,
+		},
+		{
+			options:            IndexField | IncludeTermVectors | IncludePayloads,
+			isIndexed:          true,
+			includeTermVectors: true,
+			includePayloads:    true,
+		},
 	}
 
 	for _, test := range tests {
@@ -123,5 +136,13 @@ func TestFieldIndexingOptions(t *testing.T) {
 		if actuallyUseGPU != test.useGPU {
 			t.Errorf("expected useGPU to be %v, got %v for %d", test.useGPU, actuallyUseGPU, test.options)
 		}
+		actuallyReverseTerms := test.options.IncludeReversedTerms()
+		if actuallyReverseTerms != test.reverseTerms {
+			t.Errorf("expected reverseTerms to be %v, got %v for %d", test.reverseTerms, actuallyReverseTerms, test.options)
+		}
+		actuallyIncludePayloads := test.options.IncludePayloads()
+		if actuallyIncludePayloads != test.includePayloads {
+			t.Errorf("expected includePayloads to be %v, got %v for %d", test.includePayloads, actuallyIncludePayloads, test.options)
+		}
 	}
 }
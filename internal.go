@@ -0,0 +1,204 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// InternalIterator is the interface exposing the enumeration of internal
+// key-value pairs in byte lexicographic order over their keys.
+type InternalIterator interface {
+	// Next returns the next key and its value, or a nil key when it
+	// reaches the end of the enumeration. The returned slices are only
+	// valid until the following call to Next.
+	Next() (key []byte, val []byte, err error)
+
+	// Close releases any resources associated with the iterator.
+	Close() error
+}
+
+// IndexReaderInternal provides functionality to enumerate the internal
+// key-value pairs set through SetInternal.
+type IndexReaderInternal interface {
+	// InternalIterator returns an iterator over the internal key-value
+	// pairs whose keys start with the given prefix. A nil or empty prefix
+	// enumerates all of them.
+	InternalIterator(prefix []byte) (InternalIterator, error)
+}
+
+// InternalNamespaceSeparator separates the name of an InternalNamespace
+// from the keys within it.
+const InternalNamespaceSeparator byte = 0x00
+
+// internalKeyEscape escapes the occurrences of InternalNamespaceSeparator
+// and of itself within namespaced keys, preserving their order.
+const internalKeyEscape byte = 0x01
+
+// InternalNamespace partitions the internal keyspace, so that independent
+// users of SetInternal/GetInternal do not collide on their keys. A key k
+// within the namespace "name" is stored as "name" + separator + k, with
+// the separator bytes of k escaped so that keys never collide with those
+// of nested namespaces.
+type InternalNamespace struct {
+	prefix []byte
+}
+
+// NewInternalNamespace returns the namespace with the given name. It panics
+// if the name is empty or contains InternalNamespaceSeparator, as namespace
+// names are expected to be constants.
+func NewInternalNamespace(name string) InternalNamespace {
+	if name == "" || bytes.IndexByte([]byte(name), InternalNamespaceSeparator) >= 0 {
+		panic(fmt.Sprintf("invalid internal namespace name %q", name))
+	}
+	prefix := make([]byte, 0, len(name)+1)
+	prefix = append(prefix, name...)
+	prefix = append(prefix, InternalNamespaceSeparator)
+	return InternalNamespace{prefix: prefix}
+}
+
+// Sub returns a namespace nested within this one.
+func (n InternalNamespace) Sub(name string) InternalNamespace {
+	sub := NewInternalNamespace(name)
+	prefix := make([]byte, 0, len(n.prefix)+len(sub.prefix))
+	prefix = append(prefix, n.prefix...)
+	prefix = append(prefix, sub.prefix...)
+	return InternalNamespace{prefix: prefix}
+}
+
+// Prefix returns the prefix shared by all the keys of the namespace.
+func (n InternalNamespace) Prefix() []byte {
+	return n.prefix
+}
+
+// Key returns the internal key under which key is stored in the namespace.
+func (n InternalNamespace) Key(key []byte) []byte {
+	rv := make([]byte, 0, len(n.prefix)+len(key))
+	rv = append(rv, n.prefix...)
+	for _, c := range key {
+		switch c {
+		case InternalNamespaceSeparator:
+			rv = append(rv, internalKeyEscape, 0x01)
+		case internalKeyEscape:
+			rv = append(rv, internalKeyEscape, 0x02)
+		default:
+			rv = append(rv, c)
+		}
+	}
+	return rv
+}
+
+// Strip returns the key within the namespace for the given internal key,
+// and false if the internal key does not belong to the namespace, which
+// includes the keys of nested namespaces.
+func (n InternalNamespace) Strip(key []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(key, n.prefix) {
+		return nil, false
+	}
+	key = key[len(n.prefix):]
+	if bytes.IndexByte(key, InternalNamespaceSeparator) >= 0 {
+		return nil, false
+	}
+	if bytes.IndexByte(key, internalKeyEscape) < 0 {
+		return key, true
+	}
+	rv := make([]byte, 0, len(key))
+	for i := 0; i < len(key); i++ {
+		if key[i] != internalKeyEscape {
+			rv = append(rv, key[i])
+			continue
+		}
+		if i++; i == len(key) {
+			return nil, false
+		}
+		switch key[i] {
+		case 0x01:
+			rv = append(rv, InternalNamespaceSeparator)
+		case 0x02:
+			rv = append(rv, internalKeyEscape)
+		default:
+			return nil, false
+		}
+	}
+	return rv, true
+}
+
+// Iterator returns an iterator over the key-value pairs of the namespace
+// whose keys, as seen within the namespace, start with the given prefix.
+// The keys returned by the iterator are stripped of the namespace prefix,
+// and those of nested namespaces are skipped.
+func (n InternalNamespace) Iterator(r IndexReaderInternal, prefix []byte) (InternalIterator, error) {
+	it, err := r.InternalIterator(n.Key(prefix))
+	if err != nil {
+		return nil, err
+	}
+	return &namespaceInternalIterator{ns: n, it: it}, nil
+}
+
+type namespaceInternalIterator struct {
+	ns InternalNamespace
+	it InternalIterator
+}
+
+func (i *namespaceInternalIterator) Next() ([]byte, []byte, error) {
+	for {
+		key, val, err := i.it.Next()
+		if err != nil || key == nil {
+			return nil, nil, err
+		}
+		if stripped, ok := i.ns.Strip(key); ok {
+			return stripped, val, nil
+		}
+	}
+}
+
+func (i *namespaceInternalIterator) Close() error {
+	return i.it.Close()
+}
+
+// InternalCASIndex is an optional interface for indexes that support
+// compare-and-set semantics on internal keys.
+//
+// The Batch method of an InternalCASIndex checks Batch.InternalConditions
+// atomically with applying the batch, and rejects the whole batch with an
+// *InternalConflictError if any of them does not hold.
+type InternalCASIndex interface {
+	Index
+
+	// CompareAndSetInternal sets the internal key to val, or deletes it if
+	// val is nil, provided its current value equals expected. A nil
+	// expected value requires that the key does not exist. It returns an
+	// *InternalConflictError otherwise.
+	CompareAndSetInternal(key, expected, val []byte) error
+}
+
+// InternalConflictError reports a compare-and-set operation on an internal
+// key whose current value differs from the expected one.
+type InternalConflictError struct {
+	Key      []byte
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *InternalConflictError) Error() string {
+	if e.Expected == nil {
+		return fmt.Sprintf("internal key conflict for '%s': expected it not to exist", e.Key)
+	}
+	if e.Actual == nil {
+		return fmt.Sprintf("internal key conflict for '%s': key does not exist", e.Key)
+	}
+	return fmt.Sprintf("internal key conflict for '%s': value differs from expected", e.Key)
+}
@@ -0,0 +1,102 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestInternalNamespace(t *testing.T) {
+	ns := NewInternalNamespace("app")
+	key := ns.Key([]byte("checkpoint"))
+	if string(key) != "app\x00checkpoint" {
+		t.Errorf("unexpected key %q", key)
+	}
+	stripped, ok := ns.Strip(key)
+	if !ok || string(stripped) != "checkpoint" {
+		t.Errorf("expected to strip %q, got %q, %v", key, stripped, ok)
+	}
+	if _, ok := NewInternalNamespace("ap").Strip(key); ok {
+		t.Errorf("expected key not to belong to a namespace sharing a name prefix")
+	}
+	sub := ns.Sub("offsets")
+	if string(sub.Key([]byte("p0"))) != "app\x00offsets\x00p0" {
+		t.Errorf("unexpected nested key %q", sub.Key([]byte("p0")))
+	}
+
+	// keys holding separator bytes never collide with nested namespaces
+	raw := []byte("offsets\x00p0\x01")
+	if bytes.Equal(ns.Key(raw), sub.Key([]byte("p0\x01"))) {
+		t.Errorf("expected key %q not to collide with a nested namespace", raw)
+	}
+	stripped, ok = ns.Strip(ns.Key(raw))
+	if !ok || !bytes.Equal(stripped, raw) {
+		t.Errorf("expected to strip back %q, got %q, %v", raw, stripped, ok)
+	}
+	if _, ok := ns.Strip(sub.Key([]byte("p0"))); ok {
+		t.Errorf("expected nested key not to belong to the parent namespace")
+	}
+
+	for _, name := range []string{"", "a\x00b"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for namespace name %q", name)
+				}
+			}()
+			NewInternalNamespace(name)
+		}()
+	}
+}
+
+func TestInternalNamespaceIterator(t *testing.T) {
+	ns := NewInternalNamespace("app")
+	reader := &testIndexReader{
+		internal: map[string][]byte{
+			string(ns.Key([]byte("cp-2"))):         []byte("20"),
+			string(ns.Key([]byte("cp-1"))):         []byte("10"),
+			string(ns.Key([]byte("meta"))):         []byte("m"),
+			string(ns.Key([]byte("cp-\x00"))):      []byte("30"),
+			string(ns.Sub("cp-").Key([]byte("x"))): []byte("nested"),
+			"app":                                  []byte("legacy"),
+			"_training":                            []byte("t"),
+			string(NewInternalNamespace("apps").Key([]byte("cp-0"))): []byte("x"),
+		},
+	}
+
+	it, err := ns.Iterator(reader, []byte("cp-"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	var got []string
+	for {
+		k, v, err := it.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k == nil {
+			break
+		}
+		got = append(got, string(k)+"="+string(v))
+	}
+	expected := []string{"cp-\x00=30", "cp-1=10", "cp-2=20"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
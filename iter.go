@@ -0,0 +1,116 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import "iter"
+
+// The functions below adapt the enumerations of this package to range-over-func
+// iterators, e.g.
+//
+//	for entry, err := range Dict(fd) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// An iterator takes ownership of the enumeration: it is closed once the loop
+// ends, whether by exhausting it, by breaking out of it, or by an error. An
+// error, including one returned by Close, is yielded along with a zero value
+// as the final iteration. Each iterator can be ranged over only once.
+
+// Dict returns an iterator over the entries of the FieldDict.
+func Dict(fd FieldDict) iter.Seq2[*DictEntry, error] {
+	return closingSeq(func() (*DictEntry, bool, error) {
+		entry, err := fd.Next()
+		return entry, entry != nil, err
+	}, fd.Close)
+}
+
+// TermFieldDocs returns an iterator over the documents of the
+// TermFieldReader. Each TermFieldDoc is freshly allocated and can be
+// retained.
+func TermFieldDocs(tfr TermFieldReader) iter.Seq2[*TermFieldDoc, error] {
+	return closingSeq(func() (*TermFieldDoc, bool, error) {
+		tfd, err := tfr.Next(nil)
+		return tfd, tfd != nil, err
+	}, tfr.Close)
+}
+
+// DocIDs returns an iterator over the internal ids of the DocIDReader.
+func DocIDs(dr DocIDReader) iter.Seq2[IndexInternalID, error] {
+	return closingSeq(func() (IndexInternalID, bool, error) {
+		id, err := dr.Next()
+		return id, id != nil, err
+	}, dr.Close)
+}
+
+// ThesaurusEntries returns an iterator over the keys of a thesaurus.
+func ThesaurusEntries(tk ThesaurusKeys) iter.Seq2[*ThesaurusEntry, error] {
+	return closingSeq(func() (*ThesaurusEntry, bool, error) {
+		entry, err := tk.Next()
+		return entry, entry != nil, err
+	}, tk.Close)
+}
+
+// Synonyms returns an iterator over the synonyms of the
+// ThesaurusTermReader.
+func Synonyms(r ThesaurusTermReader) iter.Seq2[string, error] {
+	return closingSeq(func() (string, bool, error) {
+		synonym, err := r.Next()
+		return synonym, synonym != "", err
+	}, r.Close)
+}
+
+// GeoShapeV2FieldDocs returns an iterator over the documents matching the
+// search previously performed on the GeoShapeV2FieldReader. Each
+// GeoShapeV2FieldDoc is freshly allocated and can be retained.
+func GeoShapeV2FieldDocs(r GeoShapeV2FieldReader) iter.Seq2[*GeoShapeV2FieldDoc, error] {
+	return closingSeq(func() (*GeoShapeV2FieldDoc, bool, error) {
+		doc, err := r.Next(nil)
+		return doc, doc != nil, err
+	}, r.Close)
+}
+
+// closingSeq builds an iterator from a next function, which reports the end
+// of the enumeration through its bool result, and the close function of the
+// underlying enumeration. The enumeration is closed however the loop ends,
+// including by a panic, and an error closing it is only yielded once the
+// loop ran to completion.
+func closingSeq[T any](next func() (T, bool, error), closer func() error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		var completed bool
+		defer func() {
+			if err := closer(); err != nil && completed {
+				yield(zero, err)
+			}
+		}()
+		for {
+			v, ok, err := next()
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !ok {
+				completed = true
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type failingFieldDict struct {
+	testFieldDict
+	nextErr  error
+	closeErr error
+}
+
+func (d *failingFieldDict) Next() (*DictEntry, error) {
+	if d.pos >= len(d.entries) && d.nextErr != nil {
+		return nil, d.nextErr
+	}
+	return d.testFieldDict.Next()
+}
+
+func (d *failingFieldDict) Close() error {
+	d.closed = true
+	return d.closeErr
+}
+
+func TestDict(t *testing.T) {
+	fd := &testFieldDict{entries: []*DictEntry{{Term: "a"}, {Term: "b"}, {Term: "c"}}}
+	var got []string
+	for entry, err := range Dict(fd) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, entry.Term)
+	}
+	if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected terms %v", got)
+	}
+	if !fd.closed {
+		t.Errorf("expected the dictionary to be closed")
+	}
+
+	fd = &testFieldDict{entries: []*DictEntry{{Term: "a"}, {Term: "b"}}}
+	for range Dict(fd) {
+		break
+	}
+	if !fd.closed {
+		t.Errorf("expected the dictionary to be closed after break")
+	}
+}
+
+func TestDictErrors(t *testing.T) {
+	errNext, errClose := errors.New("next"), errors.New("close")
+	tests := []struct {
+		fd       *failingFieldDict
+		expected error
+	}{
+		{fd: &failingFieldDict{nextErr: errNext}, expected: errNext},
+		{fd: &failingFieldDict{nextErr: errNext, closeErr: errClose}, expected: errNext},
+		{fd: &failingFieldDict{closeErr: errClose}, expected: errClose},
+	}
+	for i, test := range tests {
+		test.fd.entries = []*DictEntry{{Term: "a"}}
+		var terms int
+		var errs []error
+		for entry, err := range Dict(test.fd) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			terms++
+			if entry == nil {
+				t.Errorf("test %d: nil entry without error", i)
+			}
+		}
+		if terms != 1 || len(errs) != 1 || errs[0] != test.expected {
+			t.Errorf("test %d: expected 1 term and error %v, got %d terms and %v",
+				i, test.expected, terms, errs)
+		}
+		if !test.fd.closed {
+			t.Errorf("test %d: expected the dictionary to be closed", i)
+		}
+	}
+}
+
+func TestDictPanic(t *testing.T) {
+	fd := &failingFieldDict{closeErr: errors.New("close")}
+	fd.entries = []*DictEntry{{Term: "a"}, {Term: "b"}}
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("expected the panic to propagate, got %v", r)
+			}
+		}()
+		for range Dict(fd) {
+			panic("boom")
+		}
+	}()
+	if !fd.closed {
+		t.Errorf("expected the dictionary to be closed after panic")
+	}
+}
+
+func TestDocIDsAndTermFieldDocs(t *testing.T) {
+	var ids []uint64
+	for id, err := range DocIDs(&testDocIDReader{ids: []uint64{2, 4, 8}}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id.Value())
+	}
+	if !reflect.DeepEqual(ids, []uint64{2, 4, 8}) {
+		t.Errorf("unexpected ids %v", ids)
+	}
+
+	var docs []*TermFieldDoc
+	for tfd, err := range TermFieldDocs(&testTermFieldReader{term: "x", ids: []uint64{1, 3}, freqs: []uint64{1, 1}}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, tfd)
+	}
+	if len(docs) != 2 || docs[0] == docs[1] || docs[0].ID.Value() != 1 || docs[1].ID.Value() != 3 {
+		t.Errorf("expected two distinct docs, got %v", docs)
+	}
+}
@@ -0,0 +1,29 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build vectors
+// +build vectors
+
+package index
+
+import "iter"
+
+// VectorDocs returns an iterator over the documents of the VectorReader.
+// Each VectorDoc is freshly allocated and can be retained.
+func VectorDocs(vr VectorReader) iter.Seq2[*VectorDoc, error] {
+	return closingSeq(func() (*VectorDoc, bool, error) {
+		doc, err := vr.Next(nil)
+		return doc, doc != nil, err
+	}, vr.Close)
+}
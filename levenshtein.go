@@ -0,0 +1,137 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxFuzziness is the largest edit distance supported by the reference
+// Levenshtein automaton.
+const MaxFuzziness = 2
+
+// NewLevenshteinAutomaton returns a FuzzyAutomaton matching the strings
+// which start with prefix and are within fuzziness edits (insertions,
+// deletions and substitutions of characters) of term. With transpositions,
+// swapping two adjacent characters also counts as a single edit (optimal
+// string alignment distance).
+func NewLevenshteinAutomaton(term string, fuzziness int, prefix string,
+	transpositions bool) (FuzzyAutomaton, error) {
+	if fuzziness < 0 || fuzziness > MaxFuzziness {
+		return nil, fmt.Errorf("fuzziness %d out of range [0, %d]", fuzziness, MaxFuzziness)
+	}
+	return &levenshteinAutomaton{
+		term:           []rune(term),
+		fuzziness:      fuzziness,
+		prefix:         prefix,
+		transpositions: transpositions,
+	}, nil
+}
+
+type levenshteinAutomaton struct {
+	term           []rune
+	fuzziness      int
+	prefix         string
+	transpositions bool
+}
+
+// MatchAndDistance returns whether the string matches, along with its edit
+// distance from the term, or fuzziness+1 when it exceeds the fuzziness.
+func (a *levenshteinAutomaton) MatchAndDistance(s string) (bool, uint8) {
+	if !strings.HasPrefix(s, a.prefix) {
+		return false, uint8(a.fuzziness + 1)
+	}
+	d := a.distance([]rune(s))
+	return d <= a.fuzziness, uint8(d)
+}
+
+// distance computes the edit distance between the term and s, giving up
+// with fuzziness+1 as soon as it is bound to exceed the fuzziness.
+func (a *levenshteinAutomaton) distance(s []rune) int {
+	limit := a.fuzziness + 1
+	n, m := len(a.term), len(s)
+	if n-m >= limit || m-n >= limit {
+		return limit
+	}
+
+	// rows of the dynamic programming matrix, over the characters of s
+	prev2 := make([]int, m+1)
+	prev := make([]int, m+1)
+	curr := make([]int, m+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= n; i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= m; j++ {
+			cost := 1
+			if a.term[i-1] == s[j-1] {
+				cost = 0
+			}
+			d := min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if a.transpositions && i > 1 && j > 1 &&
+				a.term[i-1] == s[j-2] && a.term[i-2] == s[j-1] {
+				d = min(d, prev2[j-2]+1)
+			}
+			curr[j] = d
+			rowMin = min(rowMin, d)
+		}
+		if rowMin >= limit {
+			return limit
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return min(prev[m], limit)
+}
+
+// NewFuzzyFieldDict filters a FieldDict down to the terms matched by the
+// automaton, populating their EditDistance.
+func NewFuzzyFieldDict(fd FieldDict, a FuzzyAutomaton) FieldDict {
+	return &filteredFieldDict{
+		FieldDict: fd,
+		filter: func(entry *DictEntry) *DictEntry {
+			matched, distance := a.MatchAndDistance(entry.Term)
+			if !matched {
+				return nil
+			}
+			entry.EditDistance = distance
+			return entry
+		},
+	}
+}
+
+// FieldDictFuzzy returns a FieldDict for the terms of the given field which
+// start with prefix and are within fuzziness edits of term, along with its
+// automaton. It relies on IndexReaderFuzzy when the reader supports it and
+// transpositions are not requested, and filters the terms starting with
+// prefix through the reference Levenshtein automaton otherwise.
+func FieldDictFuzzy(reader IndexReader, field string, term string, fuzziness int,
+	prefix string, transpositions bool) (FieldDict, FuzzyAutomaton, error) {
+	if fr, ok := reader.(IndexReaderFuzzy); ok && !transpositions {
+		return fr.FieldDictFuzzyAutomaton(field, term, fuzziness, prefix)
+	}
+
+	a, err := NewLevenshteinAutomaton(term, fuzziness, prefix, transpositions)
+	if err != nil {
+		return nil, nil, err
+	}
+	fd, err := fieldDictOrPrefix(reader, field, prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewFuzzyFieldDict(fd, a), a, nil
+}
@@ -0,0 +1,85 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLevenshteinAutomaton(t *testing.T) {
+	tests := []struct {
+		term           string
+		fuzziness      int
+		prefix         string
+		transpositions bool
+		candidate      string
+		matches        bool
+		distance       uint8
+	}{
+		{term: "marty", fuzziness: 0, candidate: "marty", matches: true, distance: 0},
+		{term: "marty", fuzziness: 0, candidate: "marti", matches: false, distance: 1},
+		{term: "marty", fuzziness: 1, candidate: "marti", matches: true, distance: 1},
+		{term: "marty", fuzziness: 1, candidate: "mart", matches: true, distance: 1},
+		{term: "marty", fuzziness: 1, candidate: "smarty", matches: true, distance: 1},
+		{term: "marty", fuzziness: 1, candidate: "mary", matches: true, distance: 1},
+		{term: "marty", fuzziness: 1, candidate: "mraty", matches: false, distance: 2},
+		{term: "marty", fuzziness: 1, candidate: "mraty", transpositions: true, matches: true, distance: 1},
+		{term: "marty", fuzziness: 2, candidate: "mraty", matches: true, distance: 2},
+		{term: "marty", fuzziness: 2, candidate: "ma", matches: false, distance: 3},
+		{term: "marty", fuzziness: 2, candidate: "party", matches: true, distance: 1},
+		{term: "marty", fuzziness: 2, prefix: "ma", candidate: "party", matches: false, distance: 3},
+		{term: "marty", fuzziness: 2, prefix: "ma", candidate: "matt", matches: true, distance: 2},
+		{term: "café", fuzziness: 1, candidate: "cafe", matches: true, distance: 1},
+		{term: "", fuzziness: 2, candidate: "ab", matches: true, distance: 2},
+		{term: "abcdef", fuzziness: 2, candidate: "badcfe", transpositions: true, matches: false, distance: 3},
+	}
+	for _, test := range tests {
+		a, err := NewLevenshteinAutomaton(test.term, test.fuzziness, test.prefix, test.transpositions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matches, distance := a.MatchAndDistance(test.candidate)
+		if matches != test.matches || distance != test.distance {
+			t.Errorf("%q vs %q (fuzziness %d, prefix %q, transpositions %v): expected %v/%d, got %v/%d",
+				test.term, test.candidate, test.fuzziness, test.prefix, test.transpositions,
+				test.matches, test.distance, matches, distance)
+		}
+	}
+
+	if _, err := NewLevenshteinAutomaton("marty", 3, "", false); err == nil {
+		t.Errorf("expected an error for fuzziness 3")
+	}
+}
+
+func TestFieldDictFuzzy(t *testing.T) {
+	reader := newTestDictReader("name", "mary", "marty", "matt", "party", "smarty", "mraty")
+
+	fd, _, err := FieldDictFuzzy(reader, "name", "marty", 1, "m", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]uint8{}
+	for entry, err := range Dict(fd) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[entry.Term] = entry.EditDistance
+	}
+	expected := map[string]uint8{"mary": 1, "marty": 0}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,162 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import "reflect"
+
+var reflectStaticSizeRangeDocIDReader int
+
+func init() {
+	var r rangeDocIDReader
+	reflectStaticSizeRangeDocIDReader = int(reflect.TypeOf(r).Size())
+}
+
+// PartitionedReader is an extended index reader that can split the
+// document id space into disjoint partitions, which can be scanned
+// concurrently, e.g. one per segment.
+type PartitionedReader interface {
+	IndexReader
+
+	// DocIDReaderPartitions returns at most n DocIDReaders enumerating
+	// disjoint sets of documents, which together cover all the documents
+	// enumerated by DocIDReaderAll. The caller must close every returned
+	// reader.
+	DocIDReaderPartitions(n int) ([]DocIDReader, error)
+}
+
+// DocIDReaderPartitions returns at most n DocIDReaders over disjoint
+// partitions of the documents of the reader, using the native partitioning
+// of a PartitionedReader when available.
+//
+// The fallback splits the id space into ranges, enumerated by repositioning
+// a DocIDReaderAll with Advance. It assumes 8-byte internal ids, as built by
+// NewIndexInternalID, and dense ones for the purpose of balancing the
+// partitions; sparse ids only make the partitions uneven. Any other kind of
+// internal id results in a single partition.
+func DocIDReaderPartitions(reader IndexReader, n int) ([]DocIDReader, error) {
+	if pr, ok := reader.(PartitionedReader); ok {
+		return pr.DocIDReaderPartitions(n)
+	}
+
+	count, err := reader.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	if n <= 1 || count <= 1 {
+		return singleDocIDReaderPartition(reader)
+	}
+	if uint64(n) > count {
+		n = int(count)
+	}
+
+	probe, err := reader.DocIDReaderAll()
+	if err != nil {
+		return nil, err
+	}
+	first, err := probe.Next()
+	if cerr := probe.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(first) != 8 {
+		return singleDocIDReaderPartition(reader)
+	}
+
+	start := first.Value()
+	step := (count + uint64(n) - 1) / uint64(n)
+	rv := make([]DocIDReader, 0, n)
+	for i := 0; i < n; i++ {
+		dr, err := reader.DocIDReaderAll()
+		if err != nil {
+			for _, r := range rv {
+				_ = r.Close()
+			}
+			return nil, err
+		}
+		p := &rangeDocIDReader{dr: dr}
+		if i > 0 {
+			p.start = NewIndexInternalID(nil, start+uint64(i)*step)
+		}
+		if i < n-1 {
+			p.end = NewIndexInternalID(nil, start+uint64(i+1)*step)
+		}
+		rv = append(rv, p)
+	}
+	return rv, nil
+}
+
+func singleDocIDReaderPartition(reader IndexReader) ([]DocIDReader, error) {
+	dr, err := reader.DocIDReaderAll()
+	if err != nil {
+		return nil, err
+	}
+	return []DocIDReader{dr}, nil
+}
+
+// rangeDocIDReader restricts a DocIDReader to the ids in [start, end). A nil
+// start or end leaves that side of the range unbounded.
+type rangeDocIDReader struct {
+	dr      DocIDReader
+	start   IndexInternalID
+	end     IndexInternalID
+	started bool
+	done    bool
+}
+
+func (r *rangeDocIDReader) Next() (IndexInternalID, error) {
+	if !r.started && r.start != nil {
+		return r.Advance(r.start)
+	}
+	r.started = true
+	if r.done {
+		return nil, nil
+	}
+	id, err := r.dr.Next()
+	return r.bounded(id, err)
+}
+
+func (r *rangeDocIDReader) Advance(ID IndexInternalID) (IndexInternalID, error) {
+	r.started = true
+	if r.done {
+		return nil, nil
+	}
+	if r.start != nil && ID.Compare(r.start) < 0 {
+		ID = r.start
+	}
+	id, err := r.dr.Advance(ID)
+	return r.bounded(id, err)
+}
+
+func (r *rangeDocIDReader) bounded(id IndexInternalID, err error) (IndexInternalID, error) {
+	if err != nil || id == nil {
+		return nil, err
+	}
+	if r.end != nil && id.Compare(r.end) >= 0 {
+		r.done = true
+		return nil, nil
+	}
+	return id, nil
+}
+
+func (r *rangeDocIDReader) Size() int {
+	return reflectStaticSizeRangeDocIDReader + len(r.start) + len(r.end) +
+		r.dr.Size()
+}
+
+func (r *rangeDocIDReader) Close() error {
+	return r.dr.Close()
+}
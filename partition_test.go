@@ -0,0 +1,83 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDocIDReaderPartitions(t *testing.T) {
+	for _, numDocs := range []int{0, 1, 7, 10} {
+		for _, n := range []int{1, 3, 4, 16} {
+			t.Run(fmt.Sprintf("%d-docs-%d-partitions", numDocs, n), func(t *testing.T) {
+				reader := &testIndexReader{}
+				for i := 0; i < numDocs; i++ {
+					reader.docs = append(reader.docs, &testReaderDoc{id: fmt.Sprintf("doc-%d", i)})
+				}
+
+				partitions, err := DocIDReaderPartitions(reader, n)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(partitions) > n {
+					t.Errorf("expected at most %d partitions, got %d", n, len(partitions))
+				}
+				seen := map[uint64]int{}
+				for i, p := range partitions {
+					for {
+						id, err := p.Next()
+						if err != nil {
+							t.Fatal(err)
+						}
+						if id == nil {
+							break
+						}
+						if prev, ok := seen[id.Value()]; ok {
+							t.Errorf("doc %d in partitions %d and %d", id.Value(), prev, i)
+						}
+						seen[id.Value()] = i
+					}
+					if err := p.Close(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if len(seen) != numDocs {
+					t.Errorf("expected %d docs, got %d", numDocs, len(seen))
+				}
+			})
+		}
+	}
+}
+
+func TestRangeDocIDReaderAdvance(t *testing.T) {
+	r := &rangeDocIDReader{
+		dr:    &testDocIDReader{ids: []uint64{1, 3, 5, 7, 9}},
+		start: NewIndexInternalID(nil, 3),
+		end:   NewIndexInternalID(nil, 8),
+	}
+	id, err := r.Advance(NewIndexInternalID(nil, 0))
+	if err != nil || id.Value() != 3 {
+		t.Fatalf("expected advance before the range to land on 3, got %v, %v", id, err)
+	}
+	id, err = r.Advance(NewIndexInternalID(nil, 6))
+	if err != nil || id.Value() != 7 {
+		t.Fatalf("expected 7, got %v, %v", id, err)
+	}
+	id, err = r.Next()
+	if err != nil || id != nil {
+		t.Fatalf("expected end of range, got %v, %v", id, err)
+	}
+}
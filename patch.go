@@ -0,0 +1,73 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPatchFieldNotStored is returned when a patch targets a field which is
+// not stored, as such fields cannot be reconstructed from the index.
+var ErrPatchFieldNotStored = errors.New("cannot patch a field which is not stored")
+
+// ErrPatchDocumentNotFound is returned when a patch targets a document which
+// does not exist in the index.
+var ErrPatchDocumentNotFound = errors.New("cannot patch a document which does not exist")
+
+// PatchIndex is an optional interface for indexes that support partial
+// document updates. The stored fields of the existing document are merged
+// with the patch fields and only the patch fields are re-analyzed.
+//
+// The Batch method of a PatchIndex applies Batch.PatchOps in the same
+// atomic unit as the other operations of the batch.
+type PatchIndex interface {
+	Index
+
+	// Patch merges the fields into the stored document with the given id.
+	Patch(id string, fields ...Field) error
+}
+
+// ValidatePatch checks that every patch field is stored, returning an error
+// wrapping ErrPatchFieldNotStored otherwise.
+func ValidatePatch(fields []Field) error {
+	for _, f := range fields {
+		if !f.Options().IsStored() {
+			return fmt.Errorf("%w: '%s'", ErrPatchFieldNotStored, f.Name())
+		}
+	}
+	return nil
+}
+
+// VisitPatchedFields visits the fields of the document resulting from
+// applying the patch to the existing document. Every field of the existing
+// document sharing its name with a patch field is replaced by the patch
+// fields of that name. The changed flag is true for the patch fields, which
+// are the only ones that need to be analyzed again.
+func VisitPatchedFields(existing Document, patch []Field,
+	visitor func(field Field, changed bool)) {
+	patched := make(map[string]struct{}, len(patch))
+	for _, f := range patch {
+		patched[f.Name()] = struct{}{}
+	}
+	existing.VisitFields(func(f Field) {
+		if _, ok := patched[f.Name()]; !ok {
+			visitor(f, false)
+		}
+	})
+	for _, f := range patch {
+		visitor(f, true)
+	}
+}
@@ -0,0 +1,125 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+// PositionIterator enumerates the occurrences of a term within a document,
+// in the order the index keeps them, typically by increasing position and
+// grouped by array positions. Accessors describe the current occurrence,
+// and are only valid once Next has returned true.
+type PositionIterator interface {
+	// Next moves to the following occurrence, returning false once they
+	// are exhausted or an error occurred, see Err.
+	Next() bool
+
+	// Err returns the error which stopped the iteration, if any.
+	Err() error
+
+	// Pos returns the position of the occurrence.
+	Pos() uint64
+	// PositionLength returns the number of positions the occurrence spans
+	// in a token graph, 0 standing for a single position.
+	PositionLength() uint64
+	// Start returns the byte offset at which the occurrence starts.
+	Start() uint64
+	// End returns the byte offset at which the occurrence ends.
+	End() uint64
+	// ArrayPositions returns the array positions of the occurrence, which
+	// are only valid until the following call to Next.
+	ArrayPositions() []uint64
+	// Payload returns the payload of the occurrence, nil for fields which
+	// do not include payloads. It is only valid until the following call
+	// to Next.
+	Payload() []byte
+}
+
+// PositionalTermFieldReader is an optional interface for TermFieldReaders
+// which can decode the positions of a term lazily, without materializing
+// the TermFieldVectors of each document.
+type PositionalTermFieldReader interface {
+	TermFieldReader
+
+	// Positions returns an iterator over the occurrences of the term in the
+	// document last returned by Next or Advance. The iterator may be reused
+	// by the reader, and is only valid until the following call to Next,
+	// Advance or Positions.
+	Positions() PositionIterator
+}
+
+// PositionsOf returns an iterator over the occurrences of the term in the
+// document last returned by the reader. It relies on
+// PositionalTermFieldReader when the reader supports it, and otherwise
+// resets the fallback iterator over the Vectors of the TermFieldDoc, which
+// requires the reader to include term vectors.
+func PositionsOf(tfr TermFieldReader, tfd *TermFieldDoc, fallback *VectorPositionIterator) PositionIterator {
+	if ptfr, ok := tfr.(PositionalTermFieldReader); ok {
+		return ptfr.Positions()
+	}
+	fallback.Reset(tfd.Vectors)
+	return fallback
+}
+
+// VectorPositionIterator is a PositionIterator over TermFieldVectors. The
+// zero value iterates over no occurrences, and an iterator can be reused
+// with Reset.
+type VectorPositionIterator struct {
+	vectors []*TermFieldVector
+	cur     *TermFieldVector
+	next    int
+}
+
+// Reset positions the iterator before the first of the vectors.
+func (i *VectorPositionIterator) Reset(vectors []*TermFieldVector) {
+	i.vectors = vectors
+	i.cur = nil
+	i.next = 0
+}
+
+func (i *VectorPositionIterator) Next() bool {
+	if i.next >= len(i.vectors) {
+		i.cur = nil
+		return false
+	}
+	i.cur = i.vectors[i.next]
+	i.next++
+	return true
+}
+
+func (i *VectorPositionIterator) Err() error {
+	return nil
+}
+
+func (i *VectorPositionIterator) Pos() uint64 {
+	return i.cur.Pos
+}
+
+func (i *VectorPositionIterator) PositionLength() uint64 {
+	return i.cur.PositionLength
+}
+
+func (i *VectorPositionIterator) Start() uint64 {
+	return i.cur.Start
+}
+
+func (i *VectorPositionIterator) End() uint64 {
+	return i.cur.End
+}
+
+func (i *VectorPositionIterator) ArrayPositions() []uint64 {
+	return i.cur.ArrayPositions
+}
+
+func (i *VectorPositionIterator) Payload() []byte {
+	return i.cur.Payload
+}
@@ -0,0 +1,74 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import "testing"
+
+// testPositionalTermFieldReader serves the positions of a single document.
+type testPositionalTermFieldReader struct {
+	testTermFieldReader
+	positions VectorPositionIterator
+	vectors   []*TermFieldVector
+}
+
+func (t *testPositionalTermFieldReader) Positions() PositionIterator {
+	t.positions.Reset(t.vectors)
+	return &t.positions
+}
+
+func TestPositionsOf(t *testing.T) {
+	vectors := []*TermFieldVector{
+		{Field: "body", Pos: 1, Start: 0, End: 3, PositionLength: 2},
+		{Field: "body", Pos: 4, Start: 14, End: 17, Payload: []byte("NN")},
+	}
+	tfr := &testTermFieldReader{ids: []uint64{0}, freqs: []uint64{2}}
+	tfd := &TermFieldDoc{Vectors: vectors}
+	var fallback VectorPositionIterator
+
+	var positions []uint64
+	it := PositionsOf(tfr, tfd, &fallback)
+	for it.Next() {
+		positions = append(positions, it.Pos())
+		if it.Pos() == 1 && it.PositionLength() != 2 {
+			t.Errorf("expected position length 2, got %d", it.PositionLength())
+		}
+		if it.End()-it.Start() != 3 {
+			t.Errorf("expected offsets of a 3 byte term, got [%d, %d)", it.Start(), it.End())
+		}
+	}
+	if it.Err() != nil || len(positions) != 2 || positions[0] != 1 || positions[1] != 4 {
+		t.Errorf("expected positions [1 4], got %v, %v", positions, it.Err())
+	}
+
+	if vectors[0].EndPos() != 3 || vectors[1].EndPos() != 5 {
+		t.Errorf("expected end positions 3 and 5, got %d and %d", vectors[0].EndPos(), vectors[1].EndPos())
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		it := PositionsOf(tfr, tfd, &fallback)
+		for it.Next() {
+			_ = it.Pos()
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocation, got %f", allocs)
+	}
+
+	ptfr := &testPositionalTermFieldReader{vectors: vectors[1:]}
+	it = PositionsOf(ptfr, tfd, &fallback)
+	if !it.Next() || it.Pos() != 4 || string(it.Payload()) != "NN" || it.Next() {
+		t.Errorf("expected native positions to be used")
+	}
+}
@@ -0,0 +1,81 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import "regexp"
+
+// NewRegexAutomaton returns a RegexAutomaton for the pattern, which, as for
+// IndexReaderRegexp, must match whole terms rather than parts of them.
+func NewRegexAutomaton(pattern string) (RegexAutomaton, error) {
+	re, err := compileTermRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &regexAutomaton{re: re}, nil
+}
+
+type regexAutomaton struct {
+	re *regexp.Regexp
+}
+
+func (a *regexAutomaton) MatchesRegex(s string) bool {
+	return a.re.MatchString(s)
+}
+
+// RegexLiteralPrefix returns the literal prefix shared by all the terms
+// matching the pattern, and true if the pattern matches that literal only.
+func RegexLiteralPrefix(pattern string) (string, bool, error) {
+	re, err := compileTermRegexp(pattern)
+	if err != nil {
+		return "", false, err
+	}
+	prefix, complete := re.LiteralPrefix()
+	return prefix, complete, nil
+}
+
+// compileTermRegexp compiles the pattern anchored at both ends.
+func compileTermRegexp(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// FieldDictRegexp returns a FieldDict for the terms of the given field
+// matching the regex pattern, along with its automaton. It relies on
+// IndexReaderRegexp when the reader supports it, and otherwise filters the
+// terms starting with the literal prefix of the pattern.
+func FieldDictRegexp(reader IndexReader, field string, pattern string) (FieldDict, RegexAutomaton, error) {
+	if rr, ok := reader.(IndexReaderRegexp); ok {
+		return rr.FieldDictRegexpAutomaton(field, pattern)
+	}
+
+	re, err := compileTermRegexp(pattern)
+	if err != nil {
+		return nil, nil, err
+	}
+	a := &regexAutomaton{re: re}
+	prefix, _ := re.LiteralPrefix()
+	fd, err := fieldDictOrPrefix(reader, field, prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &filteredFieldDict{
+		FieldDict: fd,
+		filter: func(entry *DictEntry) *DictEntry {
+			if !a.MatchesRegex(entry.Term) {
+				return nil
+			}
+			return entry
+		},
+	}, a, nil
+}
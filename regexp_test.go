@@ -0,0 +1,96 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegexAutomaton(t *testing.T) {
+	a, err := NewRegexAutomaton("foo.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for term, expected := range map[string]bool{
+		"foo":    true,
+		"food":   true,
+		"afoo":   false,
+		"fo":     false,
+		"foo\nx": false,
+	} {
+		if got := a.MatchesRegex(term); got != expected {
+			t.Errorf("term %q: expected %v, got %v", term, expected, got)
+		}
+	}
+
+	a, err = NewRegexAutomaton("a|b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.MatchesRegex("ab") {
+		t.Errorf("expected alternation to be anchored as a whole")
+	}
+
+	if _, err := NewRegexAutomaton("foo("); err == nil {
+		t.Errorf("expected an error for an invalid pattern")
+	}
+}
+
+func TestRegexLiteralPrefix(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		prefix   string
+		complete bool
+	}{
+		{"foo", "foo", true},
+		{"foo.*", "foo", false},
+		{"fo+", "fo", false},
+		{"(?i)foo", "", false},
+		{".*foo", "", false},
+	}
+	for _, test := range tests {
+		prefix, complete, err := RegexLiteralPrefix(test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prefix != test.prefix || complete != test.complete {
+			t.Errorf("pattern %q: expected %q/%v, got %q/%v", test.pattern,
+				test.prefix, test.complete, prefix, complete)
+		}
+	}
+}
+
+func TestFieldDictRegexp(t *testing.T) {
+	reader := newTestDictReader("name", "bob", "bobby", "robert", "bobbie", "rob")
+	fd, _, err := FieldDictRegexp(reader, "name", "bob+(y|ie)?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for entry, err := range Dict(fd) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, entry.Term)
+	}
+	expected := []string{"bob", "bobbie", "bobby"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if fd.Cardinality() != 3 {
+		t.Errorf("expected a prefix walk over the 3 terms starting with 'bob', got %d", fd.Cardinality())
+	}
+}
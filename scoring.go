@@ -0,0 +1,231 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Parameters of the BM25 scoring model.
+const (
+	BM25K1 = "k1"
+	BM25B  = "b"
+)
+
+// ScoringParam describes a numeric parameter of a scoring model. A Name
+// ending with '*' declares a family of parameters sharing that prefix, such
+// as per-field weights.
+type ScoringParam struct {
+	Name    string
+	Default float64
+	// Min and Max bound the values of the parameter, inclusively. A
+	// parameter leaving both zero is unbounded.
+	Min float64
+	Max float64
+}
+
+func (p *ScoringParam) bounded() bool {
+	return p.Min != 0 || p.Max != 0
+}
+
+func (p *ScoringParam) check(v float64) error {
+	if math.IsNaN(v) || p.bounded() && (v < p.Min || v > p.Max) {
+		return fmt.Errorf("out of range [%g, %g]: %g", p.Min, p.Max, v)
+	}
+	return nil
+}
+
+func (p *ScoringParam) matches(name string) bool {
+	if prefix, ok := strings.CutSuffix(p.Name, "*"); ok {
+		return strings.HasPrefix(name, prefix) && len(name) > len(prefix)
+	}
+	return name == p.Name
+}
+
+// ScoringModel describes a scoring model which can be selected by name, and
+// the parameters it accepts.
+type ScoringModel struct {
+	Name   string
+	Params []ScoringParam
+	// Validate optionally checks the parameters as a whole, once each of
+	// them has been checked against its ScoringParam.
+	Validate func(params map[string]float64) error
+}
+
+// Resolve checks the parameters against the model, and returns them
+// completed with the defaults of the missing ones. Families of parameters
+// have no defaults.
+func (m *ScoringModel) Resolve(params map[string]float64) (map[string]float64, error) {
+	rv := make(map[string]float64, len(m.Params))
+	for name, v := range params {
+		p := m.param(name)
+		if p == nil {
+			return nil, fmt.Errorf("unknown parameter %q for scoring model %q", name, m.Name)
+		}
+		if err := p.check(v); err != nil {
+			return nil, fmt.Errorf("parameter %q of scoring model %q %w", name, m.Name, err)
+		}
+		rv[name] = v
+	}
+	for _, p := range m.Params {
+		if _, ok := rv[p.Name]; !ok && !strings.HasSuffix(p.Name, "*") {
+			rv[p.Name] = p.Default
+		}
+	}
+	if m.Validate != nil {
+		if err := m.Validate(rv); err != nil {
+			return nil, err
+		}
+	}
+	return rv, nil
+}
+
+func (m *ScoringModel) param(name string) *ScoringParam {
+	for i := range m.Params {
+		if m.Params[i].matches(name) {
+			return &m.Params[i]
+		}
+	}
+	return nil
+}
+
+var scoringModels = struct {
+	m      sync.RWMutex
+	models map[string]*ScoringModel
+}{
+	models: make(map[string]*ScoringModel),
+}
+
+func init() {
+	RegisterScoringModel(&ScoringModel{Name: TFIDFScoring})
+	RegisterScoringModel(&ScoringModel{
+		Name: BM25Scoring,
+		Params: []ScoringParam{
+			{Name: BM25K1, Default: 1.2, Min: 0, Max: math.Inf(1)},
+			{Name: BM25B, Default: 0.75, Min: 0, Max: 1},
+		},
+	})
+}
+
+// RegisterScoringModel makes a scoring model available by name, and adds it
+// to SupportedScoringModels. It is meant to be called from init functions,
+// as SupportedScoringModels is read without locking. If the model has no
+// name, a name already registered, or a parameter whose bounds or default
+// are invalid, it panics.
+func RegisterScoringModel(model *ScoringModel) {
+	if model == nil {
+		panic("index: RegisterScoringModel model is nil")
+	}
+	if model.Name == "" {
+		panic("index: RegisterScoringModel model has no name")
+	}
+	for _, p := range model.Params {
+		if math.IsNaN(p.Min) || math.IsNaN(p.Max) || p.Min > p.Max {
+			panic(fmt.Sprintf("index: RegisterScoringModel invalid bounds [%g, %g] of parameter %q of %q",
+				p.Min, p.Max, p.Name, model.Name))
+		}
+		if err := p.check(p.Default); err != nil {
+			panic(fmt.Sprintf("index: RegisterScoringModel default of parameter %q of %q %v",
+				p.Name, model.Name, err))
+		}
+	}
+	scoringModels.m.Lock()
+	defer scoringModels.m.Unlock()
+	if _, exists := scoringModels.models[model.Name]; exists {
+		panic("index: RegisterScoringModel called twice for scoring model " + model.Name)
+	}
+	scoringModels.models[model.Name] = model
+	SupportedScoringModels[model.Name] = struct{}{}
+}
+
+// ScoringModelByName returns the registered scoring model with the given
+// name, if any.
+func ScoringModelByName(name string) (*ScoringModel, bool) {
+	scoringModels.m.RLock()
+	defer scoringModels.m.RUnlock()
+	model, ok := scoringModels.models[name]
+	return model, ok
+}
+
+// RegisteredScoringModels returns the sorted names of the registered scoring
+// models.
+func RegisteredScoringModels() []string {
+	scoringModels.m.RLock()
+	defer scoringModels.m.RUnlock()
+	rv := make([]string, 0, len(scoringModels.models))
+	for name := range scoringModels.models {
+		rv = append(rv, name)
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+// unregisterScoringModel removes a scoring model from the registry, for
+// tests to restore it.
+func unregisterScoringModel(name string) {
+	scoringModels.m.Lock()
+	defer scoringModels.m.Unlock()
+	delete(scoringModels.models, name)
+	delete(SupportedScoringModels, name)
+}
+
+// FieldScoring selects the scoring model of a field, along with its
+// parameters. An empty Model selects the DefaultScoringModel.
+type FieldScoring struct {
+	Model  string             `json:"model,omitempty"`
+	Params map[string]float64 `json:"params,omitempty"`
+}
+
+// Resolve returns the scoring model of the field, and its parameters
+// completed with defaults.
+func (s *FieldScoring) Resolve() (*ScoringModel, map[string]float64, error) {
+	name := s.Model
+	if name == "" {
+		name = DefaultScoringModel
+	}
+	model, ok := ScoringModelByName(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown scoring model %q", name)
+	}
+	params, err := model.Resolve(s.Params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return model, params, nil
+}
+
+// ScoringModelIndex is an optional interface for indexes which support only
+// some of the registered scoring models.
+type ScoringModelIndex interface {
+	Index
+
+	// ScoringModels returns the names of the scoring models the index
+	// supports.
+	ScoringModels() []string
+}
+
+// IndexScoringModels returns the names of the scoring models supported by
+// the index, which are all the registered ones unless it implements
+// ScoringModelIndex.
+func IndexScoringModels(i Index) []string {
+	if si, ok := i.(ScoringModelIndex); ok {
+		return si.ScoringModels()
+	}
+	return RegisteredScoringModels()
+}
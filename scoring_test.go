@@ -0,0 +1,138 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// testRegisterScoringModel registers a model, and restores the registry
+// once the test completes.
+func testRegisterScoringModel(t *testing.T, model *ScoringModel) {
+	RegisterScoringModel(model)
+	t.Cleanup(func() {
+		unregisterScoringModel(model.Name)
+	})
+}
+
+func TestBuiltinScoringModels(t *testing.T) {
+	for _, name := range []string{BM25Scoring, TFIDFScoring} {
+		if _, ok := ScoringModelByName(name); !ok {
+			t.Errorf("expected %s to be registered", name)
+		}
+	}
+
+	model, params, err := (&FieldScoring{Model: BM25Scoring, Params: map[string]float64{BM25B: 0.5}}).Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]float64{BM25K1: 1.2, BM25B: 0.5}
+	if model.Name != BM25Scoring || !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected bm25 with %v, got %s with %v", expected, model.Name, params)
+	}
+
+	model, _, err = (&FieldScoring{}).Resolve()
+	if err != nil || model.Name != DefaultScoringModel {
+		t.Errorf("expected default scoring model, got %v, %v", model, err)
+	}
+}
+
+func TestScoringModelResolve(t *testing.T) {
+	model := &ScoringModel{
+		Name: "test-bm25f",
+		Params: []ScoringParam{
+			{Name: BM25K1, Default: 1.2, Min: 0, Max: math.Inf(1)},
+			{Name: "weight.*", Default: 1, Min: 0, Max: 100},
+			{Name: "boost", Default: 1},
+		},
+		Validate: func(params map[string]float64) error {
+			if params[BM25K1] == 0 && len(params) > 1 {
+				return fmt.Errorf("weights are meaningless without k1")
+			}
+			return nil
+		},
+	}
+	testRegisterScoringModel(t, model)
+	if _, ok := SupportedScoringModels["test-bm25f"]; !ok {
+		t.Errorf("expected registered model to be supported")
+	}
+
+	tests := []struct {
+		params   map[string]float64
+		expected map[string]float64
+		err      bool
+	}{
+		{
+			expected: map[string]float64{BM25K1: 1.2, "boost": 1},
+		},
+		{
+			params:   map[string]float64{"weight.title": 2},
+			expected: map[string]float64{BM25K1: 1.2, "boost": 1, "weight.title": 2},
+		},
+		{
+			params:   map[string]float64{"boost": -3},
+			expected: map[string]float64{BM25K1: 1.2, "boost": -3},
+		},
+		{params: map[string]float64{"boost": math.NaN()}, err: true},
+		{params: map[string]float64{"weight.title": 200}, err: true},
+		{params: map[string]float64{"weight.": 2}, err: true},
+		{params: map[string]float64{"mu": 2000}, err: true},
+		{params: map[string]float64{BM25K1: -1}, err: true},
+		{params: map[string]float64{BM25K1: math.NaN()}, err: true},
+		{params: map[string]float64{BM25K1: 0, "weight.title": 2}, err: true},
+	}
+	for _, test := range tests {
+		params, err := model.Resolve(test.params)
+		if test.err {
+			if err == nil {
+				t.Errorf("expected error for %v", test.params)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(params, test.expected) {
+			t.Errorf("expected %v, got %v", test.expected, params)
+		}
+	}
+
+	if models := IndexScoringModels(&testIndex{}); !reflect.DeepEqual(models, RegisteredScoringModels()) {
+		t.Errorf("expected all registered models, got %v", models)
+	}
+}
+
+func TestRegisterScoringModelInvalid(t *testing.T) {
+	tests := []*ScoringModel{
+		nil,
+		{},
+		{Name: BM25Scoring},
+		{Name: "test-bounds", Params: []ScoringParam{{Name: "p", Min: 1, Max: 0}}},
+		{Name: "test-default", Params: []ScoringParam{{Name: "p", Default: 2, Min: 0, Max: 1}}},
+	}
+	for i, model := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("test %d: expected registration to panic", i)
+				}
+			}()
+			testRegisterScoringModel(t, model)
+		}()
+	}
+}
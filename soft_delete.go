@@ -0,0 +1,120 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SoftDeleteRetentionKey is the index config key enabling soft deletes. Its
+// value is the retention period of soft deleted documents, either as a
+// duration string (e.g. "72h") or as a number of seconds.
+const SoftDeleteRetentionKey = "soft_delete_retention"
+
+// StatsSoftDeletedDocs is the StatsMap key under which a SoftDeleteIndex
+// reports the number of soft deleted documents awaiting purge.
+const StatsSoftDeletedDocs = "soft_deleted_docs"
+
+// ErrNotSoftDeleted is returned when undeleting a document which is not
+// retained as soft deleted, either because it was never deleted or because
+// it has already been purged.
+var ErrNotSoftDeleted = errors.New("document is not soft deleted")
+
+// SoftDeleteIndex is an optional interface for indexes that retain deleted
+// documents, along with their stored fields, for a retention period.
+//
+// Soft deleted documents are hidden from all IndexReader enumerations and
+// lookups as if they had been deleted. Readers obtained from a
+// SoftDeleteIndex implement SoftDeleteIndexReader to reach them. Indexing
+// a document with the id of a soft deleted one discards the latter.
+type SoftDeleteIndex interface {
+	Index
+
+	// Undelete restores the soft deleted document with the given id. It
+	// returns ErrNotSoftDeleted if no such document is retained.
+	Undelete(id string) error
+
+	// Purge physically removes the soft deleted documents deleted before
+	// the given time and returns the number of documents removed. Indexes
+	// purge documents older than their retention period on their own, Purge
+	// allows doing so earlier.
+	Purge(before time.Time) (int, error)
+
+	// SoftDeleteRetention returns the retention period of soft deleted
+	// documents.
+	SoftDeleteRetention() time.Duration
+}
+
+// SoftDeletedEntry describes a soft deleted document.
+type SoftDeletedEntry struct {
+	ID        string
+	DeletedAt time.Time
+}
+
+// SoftDeletedEntries is an interface for enumerating soft deleted documents
+// in the order of their ids.
+type SoftDeletedEntries interface {
+	// Next returns the next soft deleted document, or nil when the
+	// enumeration is complete.
+	Next() (*SoftDeletedEntry, error)
+
+	// Close releases any resources associated with the enumeration.
+	Close() error
+}
+
+// SoftDeleteIndexReader is an extended index reader for accessing the soft
+// deleted documents of a SoftDeleteIndex.
+type SoftDeleteIndexReader interface {
+	IndexReader
+
+	// SoftDeleted returns an enumeration of all soft deleted documents.
+	SoftDeleted() (SoftDeletedEntries, error)
+
+	// SoftDeletedDocument returns the soft deleted document with the given
+	// id, with its stored fields, or nil if no such document is retained.
+	SoftDeletedDocument(id string) (Document, error)
+}
+
+// ParseSoftDeleteRetention reads the retention period from the index config.
+// The returned bool reports whether soft deletes are enabled.
+func ParseSoftDeleteRetention(config map[string]interface{}) (time.Duration, bool, error) {
+	v, ok := config[SoftDeleteRetentionKey]
+	if !ok || v == nil {
+		return 0, false, nil
+	}
+	var rv time.Duration
+	switch v := v.(type) {
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid %s: %v", SoftDeleteRetentionKey, err)
+		}
+		rv = d
+	case float64:
+		rv = time.Duration(v * float64(time.Second))
+	case int:
+		rv = time.Duration(v) * time.Second
+	case time.Duration:
+		rv = v
+	default:
+		return 0, false, fmt.Errorf("invalid %s: unsupported type %T", SoftDeleteRetentionKey, v)
+	}
+	if rv <= 0 {
+		return 0, false, fmt.Errorf("invalid %s: retention must be positive", SoftDeleteRetentionKey)
+	}
+	return rv, true, nil
+}
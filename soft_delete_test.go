@@ -0,0 +1,48 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSoftDeleteRetention(t *testing.T) {
+	tests := []struct {
+		config    map[string]interface{}
+		retention time.Duration
+		enabled   bool
+		err       bool
+	}{
+		{config: nil},
+		{config: map[string]interface{}{SoftDeleteRetentionKey: "72h"}, retention: 72 * time.Hour, enabled: true},
+		{config: map[string]interface{}{SoftDeleteRetentionKey: float64(90)}, retention: 90 * time.Second, enabled: true},
+		{config: map[string]interface{}{SoftDeleteRetentionKey: 60}, retention: time.Minute, enabled: true},
+		{config: map[string]interface{}{SoftDeleteRetentionKey: "soon"}, err: true},
+		{config: map[string]interface{}{SoftDeleteRetentionKey: "-1h"}, err: true},
+		{config: map[string]interface{}{SoftDeleteRetentionKey: true}, err: true},
+	}
+	for i, test := range tests {
+		retention, enabled, err := ParseSoftDeleteRetention(test.config)
+		if (err != nil) != test.err {
+			t.Errorf("test %d: unexpected error %v", i, err)
+			continue
+		}
+		if retention != test.retention || enabled != test.enabled {
+			t.Errorf("test %d: expected %v/%v, got %v/%v", i,
+				test.retention, test.enabled, retention, enabled)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import "reflect"
+
+// IDFieldName is the name of the field holding the document id.
+const IDFieldName = "_id"
+
+var reflectStaticSizeStoredDocument int
+var reflectStaticSizeStoredField int
+
+func init() {
+	var sd storedDocument
+	reflectStaticSizeStoredDocument = int(reflect.TypeOf(sd).Size())
+	var sf storedField
+	reflectStaticSizeStoredField = int(reflect.TypeOf(sf).Size())
+}
+
+// NewStoredDocument returns a Document made of stored-only fields, as found
+// in an export stream. Only the id field is indexed, so the document can be
+// retrieved but not searched.
+func NewStoredDocument(id string, fields []*ExportedField) Document {
+	rv := &storedDocument{
+		id:     id,
+		fields: make([]*storedField, 0, len(fields)),
+	}
+	for _, f := range fields {
+		if f.Name == IDFieldName {
+			continue
+		}
+		rv.fields = append(rv.fields, &storedField{
+			name:           f.Name,
+			typ:            f.Type,
+			arrayPositions: f.ArrayPositions,
+			value:          f.Value,
+			options:        StoreField,
+		})
+	}
+	return rv
+}
+
+type storedDocument struct {
+	id      string
+	fields  []*storedField
+	idField bool
+}
+
+func (d *storedDocument) ID() string {
+	return d.id
+}
+
+func (d *storedDocument) Size() int {
+	rv := reflectStaticSizeStoredDocument + len(d.id)
+	for _, f := range d.fields {
+		rv += sizeOfPtr + f.size()
+	}
+	return rv
+}
+
+func (d *storedDocument) VisitFields(visitor FieldVisitor) {
+	for _, f := range d.fields {
+		visitor(f)
+	}
+}
+
+func (d *storedDocument) VisitComposite(visitor CompositeFieldVisitor) {}
+
+func (d *storedDocument) HasComposite() bool {
+	return false
+}
+
+func (d *storedDocument) NumPlainTextBytes() uint64 {
+	var rv uint64
+	for _, f := range d.fields {
+		rv += f.NumPlainTextBytes()
+	}
+	return rv
+}
+
+func (d *storedDocument) AddIDField() {
+	if d.idField {
+		return
+	}
+	d.fields = append(d.fields, &storedField{
+		name:    IDFieldName,
+		typ:     't',
+		value:   []byte(d.id),
+		options: IndexField | StoreField,
+	})
+	d.idField = true
+}
+
+func (d *storedDocument) StoredFieldsBytes() uint64 {
+	var rv uint64
+	for _, f := range d.fields {
+		rv += uint64(len(f.value))
+	}
+	return rv
+}
+
+func (d *storedDocument) Indexed() bool {
+	return true
+}
+
+type storedField struct {
+	name           string
+	typ            byte
+	arrayPositions []uint64
+	value          []byte
+	options        FieldIndexingOptions
+	frequencies    TokenFrequencies
+}
+
+func (f *storedField) size() int {
+	return reflectStaticSizeStoredField + len(f.name) +
+		len(f.arrayPositions)*sizeOfUint64 + len(f.value)
+}
+
+func (f *storedField) Name() string {
+	return f.name
+}
+
+func (f *storedField) Value() []byte {
+	return f.value
+}
+
+func (f *storedField) ArrayPositions() []uint64 {
+	return f.arrayPositions
+}
+
+func (f *storedField) EncodedFieldType() byte {
+	return f.typ
+}
+
+// Analyze produces a single token holding the whole value for indexed
+// fields, which only applies to the id field.
+func (f *storedField) Analyze() {
+	f.frequencies = TokenFrequencies{}
+	if !f.options.IsIndexed() {
+		return
+	}
+	tf := &TokenFreq{
+		Term: f.value,
+		Locations: []*TokenLocation{
+			{
+				Field:          f.name,
+				ArrayPositions: f.arrayPositions,
+				Start:          0,
+				End:            len(f.value),
+				Position:       1,
+			},
+		},
+	}
+	tf.SetFrequency(1)
+	f.frequencies[string(f.value)] = tf
+}
+
+func (f *storedField) Options() FieldIndexingOptions {
+	return f.options
+}
+
+func (f *storedField) AnalyzedLength() int {
+	return len(f.frequencies)
+}
+
+func (f *storedField) AnalyzedTokenFrequencies() TokenFrequencies {
+	return f.frequencies
+}
+
+func (f *storedField) NumPlainTextBytes() uint64 {
+	return uint64(len(f.value))
+}
@@ -0,0 +1,156 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"context"
+	"fmt"
+)
+
+// TermStats holds the statistics of a term within a field.
+type TermStats struct {
+	// DocFreq is the number of documents containing the term.
+	DocFreq uint64
+	// TotalTermFreq is the number of occurrences of the term, across all
+	// documents.
+	TotalTermFreq uint64
+}
+
+func (s TermStats) String() string {
+	return fmt.Sprintf("docFreq: %d, totalTermFreq: %d", s.DocFreq, s.TotalTermFreq)
+}
+
+// FieldStats holds the statistics of a field.
+type FieldStats struct {
+	// DocCount is the number of documents with at least one term in the
+	// field.
+	DocCount uint64
+	// SumDocFreq is the sum of the DocFreq of all the terms of the field.
+	SumDocFreq uint64
+	// SumTotalTermFreq is the sum of the TotalTermFreq of all the terms of
+	// the field, that is the sum of the lengths of the field.
+	SumTotalTermFreq uint64
+}
+
+// AvgFieldLength returns the average length of the field, over the
+// documents which have it.
+func (s FieldStats) AvgFieldLength() float64 {
+	if s.DocCount == 0 {
+		return 0
+	}
+	return float64(s.SumTotalTermFreq) / float64(s.DocCount)
+}
+
+func (s FieldStats) String() string {
+	return fmt.Sprintf("docCount: %d, sumDocFreq: %d, sumTotalTermFreq: %d",
+		s.DocCount, s.SumDocFreq, s.SumTotalTermFreq)
+}
+
+// TermStatsReader is an extended index reader exposing the term and field
+// statistics needed by scoring models such as BM25 or language models.
+type TermStatsReader interface {
+	IndexReader
+
+	// TermStats returns the statistics of the term within the given field.
+	// A term which is not found has zero statistics.
+	TermStats(field string, term []byte) (TermStats, error)
+
+	// FieldStats returns the statistics of the given field. A field which
+	// is not found has zero statistics.
+	FieldStats(field string) (FieldStats, error)
+}
+
+// ReadTermStats returns the statistics of the term within the given field,
+// relying on TermStatsReader when the reader supports it, and otherwise
+// summing the frequencies of the postings of the term.
+func ReadTermStats(ctx context.Context, reader IndexReader, field string,
+	term []byte) (TermStats, error) {
+	if sr, ok := reader.(TermStatsReader); ok {
+		return sr.TermStats(field, term)
+	}
+	var rv TermStats
+	err := visitPostings(ctx, reader, field, term, func(tfd *TermFieldDoc) {
+		rv.DocFreq++
+		rv.TotalTermFreq += tfd.Freq
+	})
+	return rv, err
+}
+
+// ReadFieldStats returns the statistics of the given field, relying on
+// TermStatsReader when the reader supports it. The fallback walks the
+// postings of every term of the field, and is only suitable for small
+// indexes or offline use.
+func ReadFieldStats(ctx context.Context, reader IndexReader,
+	field string) (rv FieldStats, err error) {
+	if sr, ok := reader.(TermStatsReader); ok {
+		return sr.FieldStats(field)
+	}
+	fd, err := reader.FieldDict(field)
+	if err != nil {
+		return rv, err
+	}
+	defer func() {
+		if cerr := fd.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	docs := make(map[string]struct{})
+	for {
+		entry, err := fd.Next()
+		if err != nil {
+			return rv, err
+		}
+		if entry == nil {
+			break
+		}
+		err = visitPostings(ctx, reader, field, []byte(entry.Term), func(tfd *TermFieldDoc) {
+			docs[string(tfd.ID)] = struct{}{}
+			rv.SumDocFreq++
+			rv.SumTotalTermFreq += tfd.Freq
+		})
+		if err != nil {
+			return rv, err
+		}
+	}
+	rv.DocCount = uint64(len(docs))
+	return rv, nil
+}
+
+// visitPostings calls the visitor with each posting of the term, including
+// its frequency. The TermFieldDoc is reused between calls.
+func visitPostings(ctx context.Context, reader IndexReader, field string,
+	term []byte, visitor func(*TermFieldDoc)) (err error) {
+	tfr, err := reader.TermFieldReader(ctx, term, field, true, false, false)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := tfr.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	var tfd TermFieldDoc
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next, err := tfr.Next(tfd.Reset())
+		if err != nil || next == nil {
+			return err
+		}
+		visitor(next)
+	}
+}
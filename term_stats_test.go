@@ -0,0 +1,76 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"context"
+	"testing"
+)
+
+func newTestStatsReader() *testIndexReader {
+	return &testIndexReader{
+		docs: []*testReaderDoc{
+			{id: "a", terms: map[string][]string{"body": {"the", "cat", "the", "hat"}}},
+			{id: "b", terms: map[string][]string{"body": {"the", "dog"}, "title": {"dog"}}},
+			{id: "c", terms: map[string][]string{"title": {"cat"}}},
+		},
+	}
+}
+
+func TestReadTermStats(t *testing.T) {
+	reader := newTestStatsReader()
+	tests := []struct {
+		field    string
+		term     string
+		expected TermStats
+	}{
+		{field: "body", term: "the", expected: TermStats{DocFreq: 2, TotalTermFreq: 3}},
+		{field: "body", term: "cat", expected: TermStats{DocFreq: 1, TotalTermFreq: 1}},
+		{field: "title", term: "the", expected: TermStats{}},
+		{field: "missing", term: "the", expected: TermStats{}},
+	}
+	for _, test := range tests {
+		stats, err := ReadTermStats(context.Background(), reader, test.field, []byte(test.term))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats != test.expected {
+			t.Errorf("%s:%s: expected %v, got %v", test.field, test.term, test.expected, stats)
+		}
+	}
+}
+
+func TestReadFieldStats(t *testing.T) {
+	reader := newTestStatsReader()
+	stats, err := ReadFieldStats(context.Background(), reader, "body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := FieldStats{DocCount: 2, SumDocFreq: 5, SumTotalTermFreq: 6}
+	if stats != expected {
+		t.Errorf("expected %v, got %v", expected, stats)
+	}
+	if avg := stats.AvgFieldLength(); avg != 3 {
+		t.Errorf("expected average field length 3, got %f", avg)
+	}
+
+	stats, err = ReadFieldStats(context.Background(), reader, "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (FieldStats{}) || stats.AvgFieldLength() != 0 {
+		t.Errorf("expected zero statistics, got %v", stats)
+	}
+}
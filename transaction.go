@@ -0,0 +1,249 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// PreparableIndex is an optional interface for indexes that can take part
+// in a transaction spanning several indexes, coordinated by a
+// TxCoordinator through two-phase commit.
+type PreparableIndex interface {
+	Index
+
+	// Prepare validates the batch and durably stages it under the given
+	// transaction id, without making it visible to readers. Once Prepare
+	// succeeds, the index must be able to Commit the transaction even after
+	// being closed and reopened.
+	Prepare(txID string, batch *Batch) error
+
+	// Commit makes the prepared batch of the transaction visible.
+	Commit(txID string) error
+
+	// Abort discards the prepared batch of the transaction. Aborting an
+	// unknown transaction is not an error.
+	Abort(txID string) error
+
+	// Prepared returns the ids of the transactions which are prepared but
+	// neither committed nor aborted, such as those in-doubt after a crash.
+	Prepared() ([]string, error)
+}
+
+// TxParticipant is the batch to be applied to one of the indexes of a
+// transaction.
+type TxParticipant struct {
+	Index PreparableIndex
+	Batch *Batch
+}
+
+// TxCoordinator applies batches to several PreparableIndexes atomically
+// using two-phase commit. The commit decisions are recorded in the internal
+// key store of a log index, which is consulted to resolve in-doubt
+// transactions on recovery. A decision records the number of participants
+// yet to commit, and is only removed once all of them have committed.
+type TxCoordinator struct {
+	log Index
+
+	m sync.Mutex
+	// stale holds the keys of decisions whose removal failed, retried by
+	// the following Execute or Recover
+	stale [][]byte
+}
+
+var txDecisionNamespace = NewInternalNamespace("_tx")
+
+func encodeTxDecision(pending int) []byte {
+	return binary.AppendUvarint(nil, uint64(pending))
+}
+
+func decodeTxDecision(val []byte) (int, error) {
+	pending, n := binary.Uvarint(val)
+	if n <= 0 || n != len(val) {
+		return 0, fmt.Errorf("invalid transaction decision %x", val)
+	}
+	return int(pending), nil
+}
+
+// NewTxCoordinator returns a coordinator recording its decisions in the
+// internal key store of the given index, which is typically one of the
+// participants.
+func NewTxCoordinator(log Index) *TxCoordinator {
+	return &TxCoordinator{
+		log: log,
+	}
+}
+
+// NewTxID returns a new random transaction id.
+func NewTxID() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
+
+// Execute prepares the batches of all participants and then commits all of
+// them, or aborts all of them if any fails to prepare. Once the decision to
+// commit has been recorded, a failure to commit a participant is returned
+// as a *TxCommitError, and the transaction is completed by Recover.
+func (c *TxCoordinator) Execute(txID string, participants []TxParticipant) error {
+	c.removeStale()
+
+	prepared := make([]PreparableIndex, 0, len(participants))
+	for _, p := range participants {
+		if err := p.Index.Prepare(txID, p.Batch); err != nil {
+			// the failed participant may have staged part of the batch
+			prepared = append(prepared, p.Index)
+			c.abort(txID, prepared)
+			return fmt.Errorf("transaction %s aborted, prepare failed: %w", txID, err)
+		}
+		prepared = append(prepared, p.Index)
+	}
+
+	key := txDecisionNamespace.Key([]byte(txID))
+	err := c.log.SetInternal(key, encodeTxDecision(len(prepared)))
+	if err != nil {
+		c.abort(txID, prepared)
+		return fmt.Errorf("transaction %s aborted, recording decision failed: %w", txID, err)
+	}
+
+	var commitErr *TxCommitError
+	for _, idx := range prepared {
+		if err := idx.Commit(txID); err != nil {
+			if commitErr == nil {
+				commitErr = &TxCommitError{TxID: txID}
+			}
+			commitErr.Errs = append(commitErr.Errs, err)
+		}
+	}
+	if commitErr != nil {
+		// best effort, a stale count leaves the decision behind rather than
+		// removing it early
+		_ = c.log.SetInternal(key, encodeTxDecision(len(commitErr.Errs)))
+		return commitErr
+	}
+
+	// the transaction is complete, even if its decision lingers for now
+	if err := c.log.DeleteInternal(key); err != nil {
+		c.m.Lock()
+		c.stale = append(c.stale, key)
+		c.m.Unlock()
+	}
+	return nil
+}
+
+// removeStale retries removing the decisions whose removal failed.
+func (c *TxCoordinator) removeStale() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	var failed [][]byte
+	for _, key := range c.stale {
+		if err := c.log.DeleteInternal(key); err != nil {
+			failed = append(failed, key)
+		}
+	}
+	c.stale = failed
+}
+
+func (c *TxCoordinator) abort(txID string, prepared []PreparableIndex) {
+	for _, idx := range prepared {
+		// best effort, leftovers are aborted by Recover
+		_ = idx.Abort(txID)
+	}
+}
+
+// Recover resolves the in-doubt transactions of the given indexes, which
+// must have been opened already: those with a recorded decision to commit
+// are committed, all others are aborted. It is meant to be called on
+// startup, before executing new transactions, and each index may recover on
+// its own: a decision is only removed from the log index once every
+// participant of its transaction has committed. Recover calls sharing a log
+// index must not run concurrently.
+func (c *TxCoordinator) Recover(indexes []PreparableIndex) error {
+	c.removeStale()
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	reader, err := c.log.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// participants yet to commit, as updated by this call
+	pending := make(map[string]int)
+	for _, idx := range indexes {
+		txIDs, err := idx.Prepared()
+		if err != nil {
+			return err
+		}
+		for _, txID := range txIDs {
+			key := txDecisionNamespace.Key([]byte(txID))
+			n, ok := pending[txID]
+			if !ok {
+				decision, err := reader.GetInternal(key)
+				if err != nil {
+					return err
+				}
+				if decision != nil {
+					if n, err = decodeTxDecision(decision); err != nil {
+						return err
+					}
+				}
+			}
+			if n == 0 {
+				if err := idx.Abort(txID); err != nil {
+					return fmt.Errorf("recovering transaction %s: %w", txID, err)
+				}
+				continue
+			}
+			if err := idx.Commit(txID); err != nil {
+				return fmt.Errorf("recovering transaction %s: %w", txID, err)
+			}
+			pending[txID] = n - 1
+			if n == 1 {
+				err = c.log.DeleteInternal(key)
+			} else {
+				err = c.log.SetInternal(key, encodeTxDecision(n-1))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TxCommitError is returned when a transaction was decided to commit but
+// some participants failed to do so. The transaction remains in-doubt for
+// those participants until TxCoordinator.Recover commits it.
+type TxCommitError struct {
+	TxID string
+	Errs []error
+}
+
+func (e *TxCommitError) Error() string {
+	return fmt.Sprintf("transaction %s committed, but %d participant(s) failed to commit, first error: %v",
+		e.TxID, len(e.Errs), e.Errs[0])
+}
+
+func (e *TxCommitError) Unwrap() []error {
+	return e.Errs
+}
@@ -0,0 +1,231 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+type testPreparableIndex struct {
+	testIndex
+	prepared   map[string]*Batch
+	prepareErr error
+	commitErr  error
+}
+
+func newTestPreparableIndex() *testPreparableIndex {
+	rv := &testPreparableIndex{prepared: make(map[string]*Batch)}
+	rv.newReader = func() (IndexReader, error) {
+		return &testIndexReader{internal: rv.internal}, nil
+	}
+	return rv
+}
+
+func (i *testPreparableIndex) Prepare(txID string, b *Batch) error {
+	if i.prepareErr != nil {
+		return i.prepareErr
+	}
+	i.prepared[txID] = b
+	return nil
+}
+
+func (i *testPreparableIndex) Commit(txID string) error {
+	if i.commitErr != nil {
+		return i.commitErr
+	}
+	if b, ok := i.prepared[txID]; ok {
+		delete(i.prepared, txID)
+		return i.Batch(b)
+	}
+	return nil
+}
+
+func (i *testPreparableIndex) Abort(txID string) error {
+	delete(i.prepared, txID)
+	return nil
+}
+
+func (i *testPreparableIndex) Prepared() ([]string, error) {
+	var rv []string
+	for txID := range i.prepared {
+		rv = append(rv, txID)
+	}
+	sort.Strings(rv)
+	return rv, nil
+}
+
+func TestTxCoordinatorExecute(t *testing.T) {
+	primary, suggest := newTestPreparableIndex(), newTestPreparableIndex()
+	c := NewTxCoordinator(primary)
+
+	b1, b2 := NewBatch(), NewBatch()
+	b1.Delete("a")
+	b2.Delete("a")
+	err := c.Execute("tx1", []TxParticipant{{primary, b1}, {suggest, b2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.prepared) != 0 || len(suggest.prepared) != 0 {
+		t.Errorf("expected no transaction left prepared")
+	}
+	// the decision is recorded and removed through the primary
+	if len(primary.batches) != 3 || len(suggest.batches) != 1 {
+		t.Errorf("expected the batches to be committed, got %d and %d",
+			len(primary.batches), len(suggest.batches))
+	}
+	if len(primary.internal) != 0 {
+		t.Errorf("expected the decision to be removed, got %v", primary.internal)
+	}
+
+	suggest.prepareErr = errors.New("disk full")
+	err = c.Execute("tx2", []TxParticipant{{primary, b1}, {suggest, b2}})
+	if err == nil {
+		t.Fatalf("expected prepare failure")
+	}
+	if len(primary.prepared) != 0 {
+		t.Errorf("expected the primary to be aborted")
+	}
+}
+
+func TestTxCoordinatorRecover(t *testing.T) {
+	primary, suggest := newTestPreparableIndex(), newTestPreparableIndex()
+	c := NewTxCoordinator(primary)
+
+	suggest.commitErr = errors.New("crash")
+	err := c.Execute("tx1", []TxParticipant{{primary, NewBatch()}, {suggest, NewBatch()}})
+	var commitErr *TxCommitError
+	if !errors.As(err, &commitErr) {
+		t.Fatalf("expected commit error, got %v", err)
+	}
+
+	// a transaction prepared without a recorded decision
+	_ = primary.Prepare("tx2", NewBatch())
+	_ = suggest.Prepare("tx2", NewBatch())
+
+	suggest.commitErr = nil
+	if err := c.Recover([]PreparableIndex{primary, suggest}); err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.prepared) != 0 || len(suggest.prepared) != 0 {
+		t.Errorf("expected all transactions to be resolved")
+	}
+	if len(suggest.batches) != 1 {
+		t.Errorf("expected tx1 to be committed and tx2 aborted, got %d batches", len(suggest.batches))
+	}
+	if len(primary.internal) != 0 {
+		t.Errorf("expected the decisions to be removed, got %v", primary.internal)
+	}
+}
+
+func TestTxCoordinatorRecoverOmittedParticipant(t *testing.T) {
+	primary, suggest := newTestPreparableIndex(), newTestPreparableIndex()
+	c := NewTxCoordinator(primary)
+
+	suggest.commitErr = errors.New("crash")
+	err := c.Execute("tx1", []TxParticipant{{primary, NewBatch()}, {suggest, NewBatch()}})
+	var commitErr *TxCommitError
+	if !errors.As(err, &commitErr) {
+		t.Fatalf("expected commit error, got %v", err)
+	}
+	suggest.commitErr = nil
+
+	// the participant holding tx1 prepared is left out
+	if err := c.Recover([]PreparableIndex{primary}); err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.internal) != 1 {
+		t.Fatalf("expected the decision of tx1 to be kept, got %v", primary.internal)
+	}
+
+	if err := c.Recover([]PreparableIndex{suggest}); err != nil {
+		t.Fatal(err)
+	}
+	if len(suggest.prepared) != 0 || len(suggest.batches) != 1 {
+		t.Errorf("expected tx1 to be committed by the late participant")
+	}
+	if len(primary.internal) != 0 {
+		t.Errorf("expected the decision to be removed, got %v", primary.internal)
+	}
+}
+
+func TestTxCoordinatorRecoverEachParticipant(t *testing.T) {
+	primary, suggest := newTestPreparableIndex(), newTestPreparableIndex()
+	c := NewTxCoordinator(primary)
+
+	// both participants crash before committing
+	primary.commitErr = errors.New("crash")
+	suggest.commitErr = errors.New("crash")
+	err := c.Execute("tx1", []TxParticipant{{primary, NewBatch()}, {suggest, NewBatch()}})
+	var commitErr *TxCommitError
+	if !errors.As(err, &commitErr) || len(commitErr.Errs) != 2 {
+		t.Fatalf("expected commit errors for both participants, got %v", err)
+	}
+	primary.commitErr = nil
+	suggest.commitErr = nil
+
+	// each participant recovers on its own
+	if err := c.Recover([]PreparableIndex{primary}); err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.prepared) != 0 {
+		t.Errorf("expected tx1 to be committed by the primary")
+	}
+	if len(primary.internal) != 1 {
+		t.Fatalf("expected the decision of tx1 to be kept, got %v", primary.internal)
+	}
+
+	if err := c.Recover([]PreparableIndex{suggest}); err != nil {
+		t.Fatal(err)
+	}
+	if len(suggest.prepared) != 0 || len(suggest.batches) != 1 {
+		t.Errorf("expected tx1 to be committed by the suggest index")
+	}
+	if len(primary.internal) != 0 {
+		t.Errorf("expected the decision to be removed, got %v", primary.internal)
+	}
+}
+
+func TestTxCoordinatorExecuteRemoveDecisionFailure(t *testing.T) {
+	primary, suggest := newTestPreparableIndex(), newTestPreparableIndex()
+	c := NewTxCoordinator(primary)
+
+	primary.batchErr = func(b *Batch) error {
+		for _, v := range b.InternalOps {
+			if v == nil {
+				return errors.New("disk full")
+			}
+		}
+		return nil
+	}
+	err := c.Execute("tx1", []TxParticipant{{primary, NewBatch()}, {suggest, NewBatch()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.internal) != 1 {
+		t.Fatalf("expected the decision of tx1 to linger, got %v", primary.internal)
+	}
+
+	// the following transaction removes it
+	primary.batchErr = nil
+	err = c.Execute("tx2", []TxParticipant{{primary, NewBatch()}, {suggest, NewBatch()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.internal) != 0 {
+		t.Errorf("expected the decisions to be removed, got %v", primary.internal)
+	}
+}
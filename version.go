@@ -0,0 +1,120 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DocumentVersionNone is the version of a document that does not exist in
+// the index. Used as an expected version, it requires that the document
+// must not exist for the operation to be applied.
+const DocumentVersionNone uint64 = 0
+
+// VersionedIndex is an optional interface for indexes that assign a
+// monotonically increasing version (sequence number) to every stored
+// document and support compare-and-set semantics on it.
+//
+// The Batch method of a VersionedIndex honours Batch.ExpectedVersions: an
+// operation whose condition does not hold is skipped, the remaining
+// operations are applied, and a *VersionConflictsError listing the skipped
+// operations is returned. Readers obtained from a VersionedIndex implement
+// VersionedIndexReader.
+type VersionedIndex interface {
+	Index
+
+	// UpdateIfVersion indexes the document only if the currently stored
+	// version of the document equals version. It returns a
+	// *VersionConflictError otherwise.
+	UpdateIfVersion(doc Document, version uint64) error
+
+	// DeleteIfVersion deletes the document only if the currently stored
+	// version of the document equals version. It returns a
+	// *VersionConflictError otherwise.
+	DeleteIfVersion(id string, version uint64) error
+}
+
+// VersionedIndexReader is an extended index reader that exposes the
+// versions of the stored documents.
+type VersionedIndexReader interface {
+	IndexReader
+
+	// DocumentVersion returns the version of the document with the given
+	// external id, or DocumentVersionNone if no such document exists.
+	DocumentVersion(id string) (uint64, error)
+}
+
+// CheckDocumentVersion returns a *VersionConflictError if the actual version
+// of the document does not satisfy the expected one.
+func CheckDocumentVersion(id string, expected, actual uint64) error {
+	if expected != actual {
+		return &VersionConflictError{
+			ID:       id,
+			Expected: expected,
+			Actual:   actual,
+		}
+	}
+	return nil
+}
+
+// VersionConflictError reports a conditional operation that was not applied
+// because the stored version of the document differs from the expected one.
+type VersionConflictError struct {
+	ID       string
+	Expected uint64
+	Actual   uint64
+}
+
+func (e *VersionConflictError) Error() string {
+	if e.Expected == DocumentVersionNone {
+		return fmt.Sprintf("version conflict for document '%s': expected it not to exist, found version %d",
+			e.ID, e.Actual)
+	}
+	if e.Actual == DocumentVersionNone {
+		return fmt.Sprintf("version conflict for document '%s': expected version %d, document does not exist",
+			e.ID, e.Expected)
+	}
+	return fmt.Sprintf("version conflict for document '%s': expected version %d, found version %d",
+		e.ID, e.Expected, e.Actual)
+}
+
+// VersionConflictsError is returned by the Batch method of a VersionedIndex
+// when one or more of the conditional operations in the batch were skipped.
+type VersionConflictsError struct {
+	Conflicts []*VersionConflictError
+}
+
+func (e *VersionConflictsError) Error() string {
+	ids := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		ids = append(ids, c.ID)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("version conflicts for %d document(s): %s",
+		len(ids), strings.Join(ids, ", "))
+}
+
+// Conflict returns the conflict recorded for the document with the given id,
+// or nil if its operation was applied.
+func (e *VersionConflictsError) Conflict(id string) *VersionConflictError {
+	for _, c := range e.Conflicts {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
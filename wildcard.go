@@ -0,0 +1,153 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"strings"
+)
+
+// ReversedFieldPrefix prefixes the name of the field holding the reversed
+// terms of a field indexed with the IncludeReversedTerms option.
+const ReversedFieldPrefix = "_rev:"
+
+// ReversedFieldName returns the name of the field holding the reversed
+// terms of the given field.
+func ReversedFieldName(field string) string {
+	return ReversedFieldPrefix + field
+}
+
+// ReverseTerm returns the term with its characters in reverse order.
+func ReverseTerm(term string) string {
+	runes := []rune(term)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// NewWildcardAutomaton returns a WildcardAutomaton for the pattern, where
+// '*' matches any sequence of characters and '?' matches exactly one.
+func NewWildcardAutomaton(pattern string) WildcardAutomaton {
+	return wildcardAutomaton([]rune(pattern))
+}
+
+type wildcardAutomaton []rune
+
+func (p wildcardAutomaton) MatchesWildcard(s string) bool {
+	str := []rune(s)
+	var i, j int
+	// position of the last '*' seen in the pattern, and of the character
+	// of the string it is currently expected to match up to
+	star, mark := -1, 0
+	for i < len(str) {
+		switch {
+		case j < len(p) && p[j] == '*':
+			star, mark = j, i
+			j++
+		case j < len(p) && (p[j] == '?' || p[j] == str[i]):
+			i++
+			j++
+		case star >= 0:
+			// let the last '*' swallow one more character
+			mark++
+			i, j = mark, star+1
+		default:
+			return false
+		}
+	}
+	for j < len(p) && p[j] == '*' {
+		j++
+	}
+	return j == len(p)
+}
+
+// WildcardLiteralPrefix returns the literal characters which the pattern
+// starts with, up to its first wildcard.
+func WildcardLiteralPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, "*?"); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// WildcardLiteralSuffix returns the literal characters which the pattern
+// ends with, after its last wildcard.
+func WildcardLiteralSuffix(pattern string) string {
+	if i := strings.LastIndexAny(pattern, "*?"); i >= 0 {
+		return pattern[i+1:]
+	}
+	return pattern
+}
+
+// FieldDictWildcard returns a FieldDict for the terms of the given field
+// matching the wildcard pattern, along with its automaton. It relies on
+// IndexReaderWildcard when the reader supports it. Otherwise it filters the
+// terms starting with the literal prefix of the pattern; a pattern without
+// such a prefix but with a literal suffix is served by a prefix walk over
+// the reversed terms of the field when the field was indexed with the
+// IncludeReversedTerms option, in which case the terms are not returned in
+// lexicographic order.
+func FieldDictWildcard(reader IndexReader, field string, pattern string) (FieldDict, WildcardAutomaton, error) {
+	if wr, ok := reader.(IndexReaderWildcard); ok {
+		return wr.FieldDictWildcardAutomaton(field, pattern)
+	}
+
+	a := NewWildcardAutomaton(pattern)
+	prefix := WildcardLiteralPrefix(pattern)
+	if suffix := WildcardLiteralSuffix(pattern); prefix == "" && suffix != "" {
+		reversedField := ReversedFieldName(field)
+		fields, err := reader.Fields()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, f := range fields {
+			if f != reversedField {
+				continue
+			}
+			fd, err := reader.FieldDictPrefix(reversedField, []byte(ReverseTerm(suffix)))
+			if err != nil {
+				return nil, nil, err
+			}
+			return &filteredFieldDict{
+				FieldDict: fd,
+				filter: func(entry *DictEntry) *DictEntry {
+					term := ReverseTerm(entry.Term)
+					if !a.MatchesWildcard(term) {
+						return nil
+					}
+					return &DictEntry{
+						Term:         term,
+						Count:        entry.Count,
+						EditDistance: entry.EditDistance,
+					}
+				},
+			}, a, nil
+		}
+	}
+
+	fd, err := fieldDictOrPrefix(reader, field, prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &filteredFieldDict{
+		FieldDict: fd,
+		filter: func(entry *DictEntry) *DictEntry {
+			if !a.MatchesWildcard(entry.Term) {
+				return nil
+			}
+			return entry
+		},
+	}, a, nil
+}
@@ -0,0 +1,116 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestWildcardAutomaton(t *testing.T) {
+	tests := []struct {
+		pattern string
+		term    string
+		matches bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", true},
+		{"*", "anything", true},
+		{"foo", "foo", true},
+		{"foo", "food", false},
+		{"foo*", "food", true},
+		{"foo*bar?", "foobarz", true},
+		{"foo*bar?", "foo-bar-barz", true},
+		{"foo*bar?", "foobar", false},
+		{"*bar", "rebar", true},
+		{"*bar", "barn", false},
+		{"?", "é", true},
+		{"?", "ab", false},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"a**c", "abc", true},
+		{"a*", "a*b", true},
+		{"日*語", "日本語", true},
+	}
+	for _, test := range tests {
+		a := NewWildcardAutomaton(test.pattern)
+		if got := a.MatchesWildcard(test.term); got != test.matches {
+			t.Errorf("pattern %q term %q: expected %v, got %v", test.pattern, test.term, test.matches, got)
+		}
+	}
+}
+
+func TestWildcardLiterals(t *testing.T) {
+	if p := WildcardLiteralPrefix("foo*bar?"); p != "foo" {
+		t.Errorf("unexpected prefix %q", p)
+	}
+	if s := WildcardLiteralSuffix("?oo*bar"); s != "bar" {
+		t.Errorf("unexpected suffix %q", s)
+	}
+	if r := ReverseTerm("añb"); r != "bña" {
+		t.Errorf("unexpected reversed term %q", r)
+	}
+}
+
+func TestFieldDictWildcard(t *testing.T) {
+	terms := []string{"football", "footnote", "handball", "baseball", "ballroom"}
+	reader := newTestDictReader("sport", terms...)
+
+	collect := func(fd FieldDict) []string {
+		var rv []string
+		for entry, err := range Dict(fd) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			rv = append(rv, entry.Term)
+		}
+		sort.Strings(rv)
+		return rv
+	}
+
+	fd, _, err := FieldDictWildcard(reader, "sport", "foot*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := collect(fd); !reflect.DeepEqual(got, []string{"football", "footnote"}) {
+		t.Errorf("unexpected terms %v", got)
+	}
+
+	fd, _, err = FieldDictWildcard(reader, "sport", "*ball")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"baseball", "football", "handball"}
+	if got := collect(fd); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected terms %v", got)
+	}
+
+	// the same lookup served by the reversed terms
+	for i, term := range terms {
+		reader.docs[i].terms[ReversedFieldName("sport")] = []string{ReverseTerm(term)}
+	}
+	fd, _, err = FieldDictWildcard(reader, "sport", "*ball")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fd.Cardinality() != 3 {
+		t.Errorf("expected a prefix walk over the 3 reversed terms ending in 'ball', got %d", fd.Cardinality())
+	}
+	if got := collect(fd); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected terms %v", got)
+	}
+}